@@ -2,119 +2,132 @@ package main
 
 import (
 	"context"
-	"encoding/binary"
-	"encoding/json"
 	"flag"
-	"fmt"
 	"log"
+	"log/slog"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
-)
 
-type Heartbeat struct {
-	Type         uint8
-	Autopilot    uint8
-	BaseMode     uint8
-	CustomMode   uint32
-	SystemStatus uint8
-}
+	"github.com/joseph-m-valdez/aetherforge/link"
+	"github.com/joseph-m-valdez/aetherforge/mavlink"
+	"github.com/joseph-m-valdez/aetherforge/router"
+)
 
 func main() {
 	// Create a context that is canceled when SIGINT or SIGTERM is received
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop() // Ensure the signal listener is stopped when main exits
-	// GCS listen port and vehicle port
-	local := flag.Int("local", 14550, "local listen port (PX4 remote_port)")
-	vehPort := flag.Int("veh-port", 14540, "vehicle udp_port (from 'mavlink status')")
-	host := flag.String("veh-host", "127.0.0.1", "vehicle host")
+
+	linkURL := flag.String("link", "udp://:14550", "transport to listen on: udp://[host]:port, tcp://host:port, or serial:///dev/ttyACM0?baud=57600")
+	vehPort := flag.Int("veh-port", 14540, "vehicle udp_port (from 'mavlink status'), used with udp:// links")
+	host := flag.String("veh-host", "127.0.0.1", "vehicle host, used with udp:// links")
+	sysID := flag.Int("sysid", 255, "GCS system id to announce as")
+	compID := flag.Int("compid", 190, "GCS component id to announce as (190 = MAV_COMP_ID_MISSIONPLANNER)")
+	httpAddr := flag.String("http", ":8080", "address to serve GET /vehicles on, empty to disable")
 	flag.Parse()
-	laddr := &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: *local}
-	conn, err := net.ListenUDP("udp", laddr)
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	codec := mavlink.NewCodec(nil) // unsigned link: no GCS<->vehicle signing key provisioned
+	l, err := link.Open(*linkURL, link.WithVerifier(mavlink.VerifyChecksum))
 	if err != nil {
-		log.Fatalf("bind %v: %v", laddr, err)
+		log.Fatalf("open %s: %v", *linkURL, err)
 	}
-	defer conn.Close()
-	raddr := &net.UDPAddr{IP: net.ParseIP(*host), Port: *vehPort}
-	fmt.Printf("listening on %s; announcing to %s…\n", laddr.String(), raddr.String())
+	defer l.Close()
+	logger.Info("listening", "link", *linkURL)
+
+	rtr := router.NewRouter(router.DefaultHeartbeatTimeout)
+	sweepDone := make(chan struct{})
+	go rtr.Run(sweepDone)
+	defer close(sweepDone)
+
+	// logEvents gets its own subscriber channel so it sees every event
+	// independently of the /vehicles/stream SSE handler's subscribers.
+	logEvts, cancelLogEvts := rtr.Subscribe()
+	defer cancelLogEvts()
+	go logEvents(logger, logEvts)
+
+	if *httpAddr != "" {
+		srv := &http.Server{Addr: *httpAddr, Handler: rtr.Handler()}
+		go func() {
+			if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error("vehicles http server exited", "err", err)
+			}
+		}()
+		go func() {
+			<-ctx.Done()
+			_ = srv.Close()
+		}()
+		logger.Info("serving vehicle table", "addr", *httpAddr)
+	}
+
+	var seq uint8
 	// === ANNOUNCE ONCE ===
-	// TODO: Send a GCS heartbeat
-	if _, err := conn.WriteToUDP([]byte{0x01}, raddr); err != nil {
+	// udp:// is connectionless, so it needs an explicit peer to announce
+	// to; tcp:// and serial:// ignore the peer argument entirely.
+	raddr := &net.UDPAddr{IP: net.ParseIP(*host), Port: *vehPort}
+	hb := &mavlink.Heartbeat{
+		Type:           6, // MAV_TYPE_GCS
+		Autopilot:      8, // MAV_AUTOPILOT_INVALID
+		BaseMode:       0,
+		SystemStatus:   4, // MAV_STATE_ACTIVE
+		MavlinkVersion: 3,
+	}
+	frame, err := codec.Encode(uint8(*sysID), uint8(*compID), seq, hb, nil)
+	if err != nil {
+		log.Fatalf("encode GCS heartbeat: %v", err)
+	}
+	seq++
+	if err := l.WriteFrame(link.Frame(frame), raddr); err != nil {
 		log.Fatalf("announce failed: %v", err)
 	}
+
 	// Channel to notify main that the read loop is done
 	done := make(chan struct{})
-	// Run the UDP read loop in a goroutine
 	go func() {
 		defer close(done)
-		buf := make([]byte, 2048)
 		for {
-			_ = conn.SetReadDeadline(time.Now().Add(3 * time.Second))
-			n, from, err := conn.ReadFromUDP(buf)
+			raw, from, err := l.ReadFrame(ctx)
 			if err != nil {
-				if ne, ok := err.(net.Error); ok && ne.Timeout() {
-					// on read timeout, check if context is done (signal received)
-					select {
-					case <-ctx.Done():
-						return
-					default:
-						continue
-					}
-				}
-				log.Printf("read: %v", err)
-				select {
-				case <-ctx.Done():
+				if ctx.Err() != nil {
 					return
-				default:
-					continue
 				}
+				logger.Warn("read failed", "err", err)
+				continue
 			}
-			if heartbeat, err := parseMAVLinkPacket(buf, n); err == nil {
-				hbJSON, _ := json.MarshalIndent(heartbeat, "", " ")
-				armed := (heartbeat.BaseMode & 0x80) != 0
-				fmt.Printf("Armed: %v\n", armed)
-				fmt.Printf("recv HEARTBEAT from %-21s:\n%s\n", from.String(), string(hbJSON))
+			decoded, err := codec.Decode(raw)
+			if err != nil {
+				continue
 			}
+			rtr.Handle(decoded, from, l)
 		}
 	}()
+
 	// Wait for signal cancellation (Ctrl-C or SIGTERM)
 	<-ctx.Done()
-	fmt.Println("\nShutting down gracefully...")
-	// Close UDP here to unblock read
-	conn.Close()
+	logger.Info("shutting down")
+	l.Close()
 	// Wait for goroutine to finish cleanly
 	<-done
-	fmt.Println("bye")
+	logger.Info("bye")
 }
 
-func parseMAVLinkPacket(buf []byte, n int) (*Heartbeat, error) {
-	// Ensure buffer is at least long enough for MAVLink v2 header (10 bytes)
-	if n < 10 || buf[0] != 0xFD {
-		return nil, fmt.Errorf("not a valid MAVLink v2 packet")
-	}
-	// Extract header fields
-	payloadLen := int(buf[1])
-	msgID := uint32(buf[7]) | uint32(buf[8])<<8 | uint32(buf[9])<<16
-
-	if msgID != 0 || payloadLen != 9 {
-		return nil, fmt.Errorf("not a HEARTBEAT message")
-	}
-
-	// Ensure buffer has enough data for header (10) + payload (9) + checksum (2)
-	if n < 10+9+2 {
-		return nil, fmt.Errorf("packet too short")
-	}
-	// Extract payload (bytes 10 to 18)
-	payload := buf[10 : 10+9]
-	heartbeat := &Heartbeat{
-		Type:         payload[4],
-		Autopilot:    payload[5],
-		BaseMode:     payload[6],
-		CustomMode:   binary.LittleEndian.Uint32(payload[0:4]),
-		SystemStatus: payload[7],
+// logEvents logs every event from a router subscription with structured
+// fields. Cancelling a subscription does not close its channel, so
+// logEvents runs until the program exits.
+func logEvents(logger *slog.Logger, events <-chan router.Event) {
+	for e := range events {
+		logger.Info(e.Type.String(),
+			"sysid", e.Session.SysID,
+			"compid", e.Session.CompID,
+			"type", e.Session.Type,
+			"autopilot", e.Session.Autopilot,
+			"armed", e.Session.Armed,
+			"custom_mode", e.Session.CustomMode,
+			"remote_addr", e.Session.RemoteAddr,
+		)
 	}
-	// TODO: Verify checksum (bytes 19-20)
-	return heartbeat, nil
 }
@@ -0,0 +1,182 @@
+// Package commands implements the MAVLink command protocol: sending a
+// COMMAND_LONG and correlating the target's COMMAND_ACK, including
+// MAV_RESULT_IN_PROGRESS progress updates.
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/joseph-m-valdez/aetherforge/conn"
+	"github.com/joseph-m-valdez/aetherforge/mavlink"
+)
+
+// MAV_RESULT values (common.xml's MAV_RESULT enum).
+const (
+	ResultAccepted          uint8 = 0
+	ResultTemporaryRejected uint8 = 1
+	ResultDenied            uint8 = 2
+	ResultUnsupported       uint8 = 3
+	ResultFailed            uint8 = 4
+	ResultInProgress        uint8 = 5
+)
+
+// Error is returned by Send when the target's COMMAND_ACK reports
+// anything other than ResultAccepted.
+type Error struct {
+	Command uint16
+	Result  uint8
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("commands: command %d: %s", e.Command, resultString(e.Result))
+}
+
+func resultString(r uint8) string {
+	switch r {
+	case ResultAccepted:
+		return "accepted"
+	case ResultTemporaryRejected:
+		return "temporarily rejected"
+	case ResultDenied:
+		return "denied"
+	case ResultUnsupported:
+		return "unsupported"
+	case ResultFailed:
+		return "failed"
+	case ResultInProgress:
+		return "in progress"
+	default:
+		return fmt.Sprintf("unknown result %d", r)
+	}
+}
+
+type ackKey struct {
+	command           uint16
+	targetSys, target uint8
+}
+
+// Client sends COMMAND_LONGs and waits for the matching COMMAND_ACK. Like
+// params.Client, it doesn't read from a link itself — feed it every
+// decoded frame via HandleFrame.
+type Client struct {
+	conn *conn.Conn
+	peer net.Addr
+
+	mu       sync.Mutex
+	waiters  map[ackKey]chan *mavlink.CommandAck
+	progress map[ackKey]chan<- uint8
+}
+
+// NewClient builds a Client that sends over c to peer.
+func NewClient(c *conn.Conn, peer net.Addr) *Client {
+	return &Client{
+		conn:     c,
+		peer:     peer,
+		waiters:  make(map[ackKey]chan *mavlink.CommandAck),
+		progress: make(map[ackKey]chan<- uint8),
+	}
+}
+
+// HandleFrame delivers f to a pending Send call if it's the COMMAND_ACK
+// it's waiting on — including MAV_RESULT_IN_PROGRESS acks, which are
+// also forwarded to the progress channel passed to Send, if any, so
+// Send's ack-timeout timer gets reset on every ack, not just the final
+// one. It returns whether f was a COMMAND_ACK at all.
+func (c *Client) HandleFrame(f *mavlink.Frame) bool {
+	ack, ok := f.Msg.(*mavlink.CommandAck)
+	if !ok {
+		return false
+	}
+	// COMMAND_ACK's own target_system/target_component identify the
+	// commander (us), not the vehicle that sent the ack — correlate
+	// against the frame's source instead, which is the target we sent
+	// the COMMAND_LONG to.
+	key := ackKey{ack.Command, f.SysID, f.CompID}
+
+	c.mu.Lock()
+	ch := c.waiters[key]
+	pch := c.progress[key]
+	c.mu.Unlock()
+
+	if ack.Result == ResultInProgress && pch != nil {
+		select {
+		case pch <- ack.Progress:
+		default:
+		}
+	}
+	if ch != nil {
+		select {
+		case ch <- ack:
+		default:
+		}
+	}
+	return true
+}
+
+// Send issues a COMMAND_LONG for command against (targetSys, targetComp)
+// with the given MAV_CMD parameters, and blocks until the target
+// accepts, rejects, or fails it. If progress is non-nil, MAV_RESULT_
+// IN_PROGRESS updates are forwarded to it (non-blocking sends — a slow
+// reader just misses intermediate updates) and the wait is extended each
+// time one arrives.
+func (c *Client) Send(ctx context.Context, targetSys, targetComp uint8, command uint16, params [7]float32, progress chan<- uint8) error {
+	key := ackKey{command, targetSys, targetComp}
+	ch := make(chan *mavlink.CommandAck, 1)
+
+	c.mu.Lock()
+	c.waiters[key] = ch
+	if progress != nil {
+		c.progress[key] = progress
+	}
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.waiters, key)
+		delete(c.progress, key)
+		c.mu.Unlock()
+	}()
+
+	msg := &mavlink.CommandLong{
+		Command:         command,
+		TargetSystem:    targetSys,
+		TargetComponent: targetComp,
+		Param1:          params[0],
+		Param2:          params[1],
+		Param3:          params[2],
+		Param4:          params[3],
+		Param5:          params[4],
+		Param6:          params[5],
+		Param7:          params[6],
+	}
+	if err := c.conn.Send(c.peer, msg); err != nil {
+		return err
+	}
+
+	const ackTimeout = 5 * time.Second
+	timer := time.NewTimer(ackTimeout)
+	defer timer.Stop()
+	for {
+		select {
+		case ack := <-ch:
+			switch ack.Result {
+			case ResultAccepted:
+				return nil
+			case ResultInProgress:
+				if !timer.Stop() {
+					<-timer.C
+				}
+				timer.Reset(ackTimeout)
+			default:
+				return &Error{Command: command, Result: ack.Result}
+			}
+		case <-timer.C:
+			return fmt.Errorf("commands: command %d: no ack after %v", command, ackTimeout)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
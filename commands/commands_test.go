@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/joseph-m-valdez/aetherforge/conn"
+	"github.com/joseph-m-valdez/aetherforge/link"
+	"github.com/joseph-m-valdez/aetherforge/mavlink"
+)
+
+// TestSendResetsTimeoutOnInProgressAck reproduces the ack-timeout-reset
+// regression: a vehicle that keeps answering MAV_RESULT_IN_PROGRESS well
+// past Send's ackTimeout (5s) must not time Send out, as long as each
+// in-progress ack keeps arriving before the next deadline — HandleFrame
+// has to forward those acks to Send's waiter channel, not just the
+// caller's progress channel.
+func TestSendResetsTimeoutOnInProgressAck(t *testing.T) {
+	gcsLink, err := link.Open("udp://127.0.0.1:18401")
+	if err != nil {
+		t.Fatalf("link.Open gcs: %v", err)
+	}
+	defer gcsLink.Close()
+	vehLink, err := link.Open("udp://127.0.0.1:18402")
+	if err != nil {
+		t.Fatalf("link.Open veh: %v", err)
+	}
+	defer vehLink.Close()
+
+	gcsConn := conn.New(gcsLink, mavlink.NewCodec(nil), 255, 190)
+	vehConn := conn.New(vehLink, mavlink.NewCodec(nil), 1, 1)
+	peer := &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 18402}
+
+	c := NewClient(gcsConn, peer)
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	go func() {
+		for {
+			f, _, err := gcsConn.ReadFrame(ctx)
+			if err != nil {
+				return
+			}
+			c.HandleFrame(f)
+		}
+	}()
+
+	// The vehicle sends one MAV_RESULT_IN_PROGRESS right away, a second
+	// one 3 seconds later (inside the 5s ackTimeout, so it should reset
+	// it), then accepts 3 seconds after that — 6 seconds total, which
+	// would already have timed out a Send that didn't honor in-progress
+	// acks as timer resets.
+	go func() {
+		f, from, err := vehConn.ReadFrame(ctx)
+		if err != nil || from == nil {
+			return
+		}
+		cmd, ok := f.Msg.(*mavlink.CommandLong)
+		if !ok {
+			return
+		}
+		ack := func(result uint8, progress uint8) {
+			vehConn.Send(from, &mavlink.CommandAck{Command: cmd.Command, Result: result, Progress: progress})
+		}
+		ack(ResultInProgress, 10)
+		time.Sleep(3 * time.Second)
+		ack(ResultInProgress, 50)
+		time.Sleep(3 * time.Second)
+		ack(ResultAccepted, 100)
+	}()
+
+	progress := make(chan uint8, 8)
+	var params [7]float32
+	err = c.Send(ctx, 1, 1, 400, params, progress)
+	if err != nil {
+		t.Fatalf("Send: %v, want nil (in-progress acks should have kept it alive)", err)
+	}
+
+	var got []uint8
+	close(progress)
+	for p := range progress {
+		got = append(got, p)
+	}
+	if len(got) < 2 {
+		t.Errorf("progress updates = %v, want at least 2", got)
+	}
+}
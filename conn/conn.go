@@ -0,0 +1,77 @@
+// Package conn wires a link.Link and a mavlink.Codec together into the
+// one thing every MAVLink client (params, commands, mission) actually
+// needs: somewhere to decode inbound frames from and an outgoing
+// sequence counter to encode to, so each doesn't reinvent that plumbing.
+package conn
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+
+	"github.com/joseph-m-valdez/aetherforge/link"
+	"github.com/joseph-m-valdez/aetherforge/mavlink"
+)
+
+// Conn is one GCS-side endpoint of a link: our own (sysid, compid), the
+// codec for that link, and the per-link outgoing sequence counter
+// MAVLink expects senders to maintain.
+type Conn struct {
+	Codec  *mavlink.Codec
+	Link   link.Link
+	SysID  uint8
+	CompID uint8
+
+	// Sign configures outgoing signing for every Send call; nil sends
+	// unsigned frames. Send only reads Sign and never mutates it, so
+	// params.Client, commands.Client, and mission.Uploader can share one
+	// Conn across goroutines. Instead each Send call adds
+	// its own tick off signCounter to Sign.Timestamp to get a strictly
+	// increasing per-frame timestamp without touching the shared struct.
+	Sign *mavlink.SignParams
+
+	seq         uint32
+	signCounter uint64
+}
+
+// New builds a Conn for l, identifying ourselves as (sysID, compID).
+func New(l link.Link, codec *mavlink.Codec, sysID, compID uint8) *Conn {
+	return &Conn{Codec: codec, Link: l, SysID: sysID, CompID: compID}
+}
+
+// Send encodes msg with the next sequence number and writes it to peer.
+// If Sign is set, each call gets its own copy of it with Timestamp
+// advanced by an atomic per-Conn counter, so concurrent Send calls over
+// one signed Conn (such as one shared by the params, commands, and
+// mission clients) each get a strictly greater timestamp
+// than the last, as mavlink.Codec.Decode requires of a spec-compliant
+// sender, without a data race on the shared *SignParams.
+func (c *Conn) Send(peer net.Addr, msg mavlink.Message) error {
+	seq := uint8(atomic.AddUint32(&c.seq, 1))
+
+	sign := c.Sign
+	if sign != nil {
+		local := *sign
+		local.Timestamp += atomic.AddUint64(&c.signCounter, 1)
+		sign = &local
+	}
+
+	frame, err := c.Codec.Encode(c.SysID, c.CompID, seq, msg, sign)
+	if err != nil {
+		return err
+	}
+	return c.Link.WriteFrame(link.Frame(frame), peer)
+}
+
+// ReadFrame reads and decodes the next frame from the link.
+func (c *Conn) ReadFrame(ctx context.Context) (*mavlink.Frame, net.Addr, error) {
+	raw, from, err := c.Link.ReadFrame(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	frame, err := c.Codec.Decode(raw)
+	if err != nil {
+		return nil, from, err
+	}
+	return frame, from, nil
+}
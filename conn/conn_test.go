@@ -0,0 +1,50 @@
+package conn
+
+import (
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/joseph-m-valdez/aetherforge/link"
+	"github.com/joseph-m-valdez/aetherforge/mavlink"
+)
+
+// TestSendConcurrentSigningIsRaceFree drives Send from several goroutines
+// at once over one signed Conn — the way params.Client, commands.Client,
+// and mission.Uploader can share a Conn — and checks that Send left the
+// shared SignParams.Timestamp untouched.
+// Run with -race to catch a regression of the shared *SignParams data
+// race this test was added to guard against.
+func TestSendConcurrentSigningIsRaceFree(t *testing.T) {
+	l, err := link.Open("udp://127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("link.Open: %v", err)
+	}
+	defer l.Close()
+
+	var key [32]byte
+	c := New(l, mavlink.NewCodec(nil), 255, 190)
+	c.Sign = &mavlink.SignParams{LinkID: 1, Key: key, Timestamp: 1000}
+
+	peer := &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 1}
+	const sendsPerWorker = 50
+	const workers = 8
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < sendsPerWorker; j++ {
+				if err := c.Send(peer, &mavlink.Heartbeat{Type: 6}); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if c.Sign.Timestamp != 1000 {
+		t.Errorf("Send mutated the shared SignParams.Timestamp to %d, want it left at 1000", c.Sign.Timestamp)
+	}
+}
@@ -0,0 +1,54 @@
+package link
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/joseph-m-valdez/aetherforge/mavlink"
+)
+
+// DefaultBatchSize is how many frames ReadBatch/WriteBatch move in one
+// syscall on platforms that support it. 280 bytes (mavlink.MaxFrameLen)
+// times this many buffers is a small, fixed amount of memory to keep
+// pinned in the pool below, even at ATTITUDE/HIGHRES_IMU/ODOMETRY rates.
+const DefaultBatchSize = 64
+
+// framePool holds MaxFrameLen-sized receive buffers for the batched read
+// path. The Linux backend keeps its buffers for the link's lifetime and
+// returns them via Close; the non-Linux fallback borrows one per
+// ReadBatch call and returns it before the call ends. Each frame handed
+// back to the caller is copied into its own newly allocated slice.
+var framePool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, mavlink.MaxFrameLen)
+		return &b
+	},
+}
+
+// BatchLink is implemented by transports that can move several frames per
+// syscall. udpLink is the only one today — recvmmsg/sendmmsg are a
+// datagram-socket concept, so TCP and serial links stay on the plain
+// Link interface.
+type BatchLink interface {
+	Link
+	// BatchSize is the largest slice ReadBatch will fill in one call.
+	BatchSize() int
+	// ReadBatch fills up to BatchSize frames in one syscall where the
+	// platform supports it. A zero-length, nil-error result means
+	// "nothing arrived before the internal poll deadline, try again" —
+	// callers should loop rather than treat it as EOF.
+	ReadBatch(ctx context.Context) ([]Frame, []net.Addr, error)
+	// WriteBatch flushes all of frames in as few syscalls as the
+	// platform allows. peers[i] addresses frames[i].
+	WriteBatch(frames []Frame, peers []net.Addr) error
+}
+
+// batchBackend is the platform-specific half of udpLink's batch support;
+// see udp_batch_linux.go and udp_batch_other.go.
+type batchBackend interface {
+	BatchSize() int
+	ReadBatch(ctx context.Context) ([]Frame, []net.Addr, error)
+	WriteBatch(frames []Frame, peers []net.Addr) error
+	Close() error
+}
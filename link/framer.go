@@ -0,0 +1,85 @@
+package link
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/joseph-m-valdez/aetherforge/mavlink"
+)
+
+// streamFramer extracts MAVLink v2 frames from a byte stream (TCP, serial)
+// where, unlike UDP, there's no guarantee one read returns exactly one
+// frame. It scans for the 0xFD start byte, reads the declared frame
+// length from the header, and — if a Verifier is configured — resyncs
+// past a bad candidate one byte at a time rather than trusting a
+// corrupted length byte.
+type streamFramer struct {
+	r      io.Reader
+	buf    []byte
+	verify Verifier
+}
+
+func newStreamFramer(r io.Reader, verify Verifier) *streamFramer {
+	return &streamFramer{r: r, verify: verify}
+}
+
+// next blocks until it has assembled one candidate frame, or the
+// underlying reader returns an error (including io.EOF on a closed
+// connection).
+func (f *streamFramer) next() (Frame, error) {
+	for {
+		if err := f.fill(1); err != nil {
+			return nil, err
+		}
+		idx := bytes.IndexByte(f.buf, mavlink.STX)
+		if idx < 0 {
+			// No start byte buffered at all; drop everything we've seen,
+			// it's all noise (or an unsupported v1 0xFE frame).
+			f.buf = f.buf[:0]
+			continue
+		}
+		f.buf = f.buf[idx:]
+
+		if err := f.fill(mavlink.HeaderLen); err != nil {
+			return nil, err
+		}
+		payloadLen := int(f.buf[1])
+		signed := f.buf[2]&0x01 != 0
+		total := mavlink.HeaderLen + payloadLen + mavlink.ChecksumLen
+		if signed {
+			total += mavlink.SignatureLen
+		}
+
+		if err := f.fill(total); err != nil {
+			return nil, err
+		}
+		candidate := make(Frame, total)
+		copy(candidate, f.buf[:total])
+
+		if f.verify != nil && !f.verify(candidate) {
+			// Not actually a frame start (or it's corrupted) — resync by
+			// advancing past this 0xFD and keep scanning from there.
+			f.buf = f.buf[1:]
+			continue
+		}
+
+		f.buf = f.buf[total:]
+		return candidate, nil
+	}
+}
+
+// fill reads from the underlying stream until at least n bytes are
+// buffered.
+func (f *streamFramer) fill(n int) error {
+	for len(f.buf) < n {
+		chunk := make([]byte, 4096)
+		m, err := f.r.Read(chunk)
+		if m > 0 {
+			f.buf = append(f.buf, chunk[:m]...)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Package link abstracts the transports a MAVLink endpoint can talk
+// over — UDP, TCP, and serial — behind one interface so the rest of the
+// program doesn't care whether it's facing a SITL instance on loopback or
+// a Pixhawk on /dev/ttyACM0.
+package link
+
+import (
+	"context"
+	"net"
+)
+
+// Frame is one raw, still-encoded MAVLink packet as it appears on the
+// wire. Decoding (and CRC/signature verification) is the mavlink
+// package's job, not this one's.
+type Frame []byte
+
+// Link reads and writes MAVLink frames over some underlying transport.
+// Implementations own their I/O loop's lifecycle: ReadFrame blocks until a
+// frame arrives, ctx is canceled, or the link hits an error it does not
+// recover from. TCP and serial links reconnect or reopen internally after
+// a read error instead of returning it.
+type Link interface {
+	// ReadFrame returns the next frame and the peer it came from.
+	// Transports with a single fixed remote endpoint return the same peer
+	// for every frame: the remote address for TCP, the device path for
+	// serial.
+	ReadFrame(ctx context.Context) (Frame, net.Addr, error)
+	// WriteFrame sends f to peer. peer is ignored by transports with a
+	// single fixed remote endpoint (serial, connected TCP).
+	WriteFrame(f Frame, peer net.Addr) error
+	Close() error
+}
+
+// Verifier reports whether a candidate frame is well-formed, i.e. passes
+// its CRC_EXTRA checksum. Byte-stream transports (serial, TCP) use it to
+// resync after corruption: a false result means "this isn't really a
+// frame start, keep scanning from the next byte."
+type Verifier func(frame []byte) bool
+
+type options struct {
+	verify Verifier
+}
+
+// Option configures a Link constructed by Open.
+type Option func(*options)
+
+// WithVerifier wires a frame verifier (typically mavlink.VerifyChecksum,
+// the CRC_EXTRA check) into the byte-stream framers so they can resync past garbage
+// instead of trusting the length byte blindly.
+func WithVerifier(v Verifier) Option {
+	return func(o *options) { o.verify = v }
+}
@@ -0,0 +1,129 @@
+package link
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/tarm/serial"
+)
+
+// serialAddr satisfies net.Addr for a serial port, which has no notion of
+// a remote address but still needs to plug into the Link interface.
+type serialAddr string
+
+func (a serialAddr) Network() string { return "serial" }
+func (a serialAddr) String() string  { return string(a) }
+
+// serialLink talks MAVLink over a real flight controller's USB/UART port
+// (e.g. /dev/ttyACM0). It reopens the port transparently if the FC
+// reboots or the USB cable is unplugged and replugged, the same way
+// tcpLink reconnects a dropped socket.
+type serialLink struct {
+	device string
+	baud   int
+	verify Verifier
+	addr   serialAddr
+
+	mu     sync.Mutex
+	port   *serial.Port
+	framer *streamFramer
+}
+
+// initialOpenTimeout bounds newSerialLink's first open attempt so Open
+// doesn't block forever (with no way to cancel, not even Ctrl-C) waiting
+// on a port that isn't plugged in yet. If it doesn't open in time, Open
+// still succeeds: ReadFrame's port == nil path retries under the
+// caller's own ctx, which is cancelable.
+const initialOpenTimeout = 5 * time.Second
+
+func newSerialLink(device string, baud int, o options) (*serialLink, error) {
+	l := &serialLink{device: device, baud: baud, verify: o.verify, addr: serialAddr(device)}
+	ctx, cancel := context.WithTimeout(context.Background(), initialOpenTimeout)
+	defer cancel()
+	_ = l.open(ctx)
+	return l, nil
+}
+
+func (l *serialLink) open(ctx context.Context) error {
+	backoff := 500 * time.Millisecond
+	const maxBackoff = 5 * time.Second
+	for {
+		port, err := serial.OpenPort(&serial.Config{Name: l.device, Baud: l.baud, ReadTimeout: 3 * time.Second})
+		if err == nil {
+			l.mu.Lock()
+			l.port = port
+			l.framer = newStreamFramer(port, l.verify)
+			l.mu.Unlock()
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		if backoff < maxBackoff {
+			backoff *= 2
+		}
+	}
+}
+
+func (l *serialLink) ReadFrame(ctx context.Context) (Frame, net.Addr, error) {
+	for {
+		l.mu.Lock()
+		port, framer := l.port, l.framer
+		l.mu.Unlock()
+
+		if port == nil {
+			if err := l.open(ctx); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		stop := make(chan struct{})
+		go func() {
+			select {
+			case <-ctx.Done():
+				port.Close()
+			case <-stop:
+			}
+		}()
+		frame, err := framer.next()
+		close(stop)
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, nil, ctx.Err()
+			}
+			l.mu.Lock()
+			l.port = nil
+			l.mu.Unlock()
+			port.Close()
+			continue // the next loop iteration reopens the device
+		}
+		return frame, l.addr, nil
+	}
+}
+
+func (l *serialLink) WriteFrame(f Frame, _ net.Addr) error {
+	l.mu.Lock()
+	port := l.port
+	l.mu.Unlock()
+	if port == nil {
+		return fmt.Errorf("link: serial %s not open", l.device)
+	}
+	_, err := port.Write(f)
+	return err
+}
+
+func (l *serialLink) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.port == nil {
+		return nil
+	}
+	return l.port.Close()
+}
@@ -0,0 +1,121 @@
+package link
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// tcpLink is a MAVLink TCP bridge (e.g. a SITL instance or a telemetry
+// forwarder listening on 5760). Unlike UDP it's a single byte stream, so
+// frames are extracted with streamFramer, and the peer can legitimately
+// vanish — ReadFrame reconnects transparently, as serialLink reopens its
+// port, so callers don't need their own retry loop.
+type tcpLink struct {
+	addr   string
+	verify Verifier
+
+	mu     sync.Mutex
+	conn   net.Conn
+	framer *streamFramer
+}
+
+// initialDialTimeout bounds newTCPLink's first connect attempt so Open
+// doesn't block forever (with no way to cancel, not even Ctrl-C) waiting
+// on a peer that isn't up yet. If it doesn't connect in time, Open still
+// succeeds: ReadFrame's conn == nil path retries under the caller's own
+// ctx, which is cancelable.
+const initialDialTimeout = 5 * time.Second
+
+func newTCPLink(hostPort string, o options) (*tcpLink, error) {
+	l := &tcpLink{addr: hostPort, verify: o.verify}
+	ctx, cancel := context.WithTimeout(context.Background(), initialDialTimeout)
+	defer cancel()
+	_ = l.dial(ctx)
+	return l, nil
+}
+
+// dial connects (or reconnects) to addr, backing off between attempts
+// until it succeeds or ctx is canceled.
+func (l *tcpLink) dial(ctx context.Context) error {
+	backoff := 500 * time.Millisecond
+	const maxBackoff = 10 * time.Second
+	for {
+		conn, err := net.DialTimeout("tcp", l.addr, 5*time.Second)
+		if err == nil {
+			l.mu.Lock()
+			l.conn = conn
+			l.framer = newStreamFramer(conn, l.verify)
+			l.mu.Unlock()
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		if backoff < maxBackoff {
+			backoff *= 2
+		}
+	}
+}
+
+func (l *tcpLink) ReadFrame(ctx context.Context) (Frame, net.Addr, error) {
+	for {
+		l.mu.Lock()
+		conn, framer := l.conn, l.framer
+		l.mu.Unlock()
+
+		if conn == nil {
+			if err := l.dial(ctx); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		stop := make(chan struct{})
+		go func() {
+			select {
+			case <-ctx.Done():
+				conn.Close()
+			case <-stop:
+			}
+		}()
+		frame, err := framer.next()
+		close(stop)
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, nil, ctx.Err()
+			}
+			l.mu.Lock()
+			l.conn = nil
+			l.mu.Unlock()
+			conn.Close()
+			continue // the next loop iteration reconnects
+		}
+		return frame, conn.RemoteAddr(), nil
+	}
+}
+
+func (l *tcpLink) WriteFrame(f Frame, _ net.Addr) error {
+	l.mu.Lock()
+	conn := l.conn
+	l.mu.Unlock()
+	if conn == nil {
+		return fmt.Errorf("link: tcp %s not connected", l.addr)
+	}
+	_, err := conn.Write(f)
+	return err
+}
+
+func (l *tcpLink) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn == nil {
+		return nil
+	}
+	return l.conn.Close()
+}
@@ -0,0 +1,77 @@
+package link
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// udpLink is the common GCS<->SITL/companion-computer case: one UDP
+// socket, one or more peers distinguished by their source address. One
+// syscall, one datagram, one frame — no stream framing needed.
+type udpLink struct {
+	conn  *net.UDPConn
+	batch batchBackend
+}
+
+func newUDPLink(hostPort string) (*udpLink, error) {
+	laddr, err := net.ResolveUDPAddr("udp", hostPort)
+	if err != nil {
+		return nil, fmt.Errorf("link: resolve %q: %w", hostPort, err)
+	}
+	conn, err := net.ListenUDP("udp", laddr)
+	if err != nil {
+		return nil, fmt.Errorf("link: bind %v: %w", laddr, err)
+	}
+	return &udpLink{conn: conn, batch: newBatchBackend(conn)}, nil
+}
+
+// BatchSize, ReadBatch, and WriteBatch make udpLink satisfy BatchLink.
+// The actual syscall strategy (recvmmsg/sendmmsg vs. a scalar loop) lives
+// in the platform-specific batchBackend built by newBatchBackend.
+func (l *udpLink) BatchSize() int { return l.batch.BatchSize() }
+
+func (l *udpLink) ReadBatch(ctx context.Context) ([]Frame, []net.Addr, error) {
+	return l.batch.ReadBatch(ctx)
+}
+
+func (l *udpLink) WriteBatch(frames []Frame, peers []net.Addr) error {
+	return l.batch.WriteBatch(frames, peers)
+}
+
+func (l *udpLink) ReadFrame(ctx context.Context) (Frame, net.Addr, error) {
+	buf := make([]byte, 2048)
+	for {
+		_ = l.conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+		n, from, err := l.conn.ReadFromUDP(buf)
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				select {
+				case <-ctx.Done():
+					return nil, nil, ctx.Err()
+				default:
+					continue
+				}
+			}
+			return nil, nil, err
+		}
+		frame := make(Frame, n)
+		copy(frame, buf[:n])
+		return frame, from, nil
+	}
+}
+
+func (l *udpLink) WriteFrame(f Frame, peer net.Addr) error {
+	raddr, ok := peer.(*net.UDPAddr)
+	if !ok {
+		return fmt.Errorf("link: udp WriteFrame needs a *net.UDPAddr peer, got %T", peer)
+	}
+	_, err := l.conn.WriteToUDP(f, raddr)
+	return err
+}
+
+func (l *udpLink) Close() error {
+	_ = l.batch.Close()
+	return l.conn.Close()
+}
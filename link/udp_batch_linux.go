@@ -0,0 +1,87 @@
+//go:build linux
+
+package link
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"golang.org/x/net/ipv4"
+)
+
+// linuxBatchUDP does vectorized UDP I/O via recvmmsg(2)/sendmmsg(2),
+// wrapped by golang.org/x/net/ipv4's PacketConn.ReadBatch/WriteBatch.
+// Receive buffers come from framePool and are reused across ReadBatch
+// calls; each received frame is still copied into a newly allocated
+// slice before it is returned.
+type linuxBatchUDP struct {
+	pc        *ipv4.PacketConn
+	batchSize int
+	bufs      []*[]byte
+	msgs      []ipv4.Message
+}
+
+func newBatchBackend(conn *net.UDPConn) batchBackend {
+	bs := DefaultBatchSize
+	bufs := make([]*[]byte, bs)
+	msgs := make([]ipv4.Message, bs)
+	for i := range msgs {
+		buf := framePool.Get().(*[]byte)
+		bufs[i] = buf
+		msgs[i].Buffers = [][]byte{*buf}
+	}
+	return &linuxBatchUDP{pc: ipv4.NewPacketConn(conn), batchSize: bs, bufs: bufs, msgs: msgs}
+}
+
+func (b *linuxBatchUDP) BatchSize() int { return b.batchSize }
+
+func (b *linuxBatchUDP) ReadBatch(ctx context.Context) ([]Frame, []net.Addr, error) {
+	_ = b.pc.SetReadDeadline(time.Now().Add(3 * time.Second))
+	n, err := b.pc.ReadBatch(b.msgs, 0)
+	if err != nil {
+		if ne, ok := err.(net.Error); ok && ne.Timeout() {
+			select {
+			case <-ctx.Done():
+				return nil, nil, ctx.Err()
+			default:
+				return nil, nil, nil
+			}
+		}
+		return nil, nil, err
+	}
+	frames := make([]Frame, n)
+	peers := make([]net.Addr, n)
+	for i := 0; i < n; i++ {
+		frame := make(Frame, b.msgs[i].N)
+		copy(frame, b.msgs[i].Buffers[0][:b.msgs[i].N])
+		frames[i] = frame
+		peers[i] = b.msgs[i].Addr
+	}
+	return frames, peers, nil
+}
+
+func (b *linuxBatchUDP) WriteBatch(frames []Frame, peers []net.Addr) error {
+	msgs := make([]ipv4.Message, len(frames))
+	for i, f := range frames {
+		msgs[i].Buffers = [][]byte{f}
+		if i < len(peers) {
+			msgs[i].Addr = peers[i]
+		}
+	}
+	for len(msgs) > 0 {
+		n, err := b.pc.WriteBatch(msgs, 0)
+		if err != nil {
+			return err
+		}
+		msgs = msgs[n:]
+	}
+	return nil
+}
+
+func (b *linuxBatchUDP) Close() error {
+	for _, buf := range b.bufs {
+		framePool.Put(buf)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+//go:build !linux
+
+package link
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// scalarBatchUDP is the non-Linux fallback: there's no recvmmsg/sendmmsg
+// equivalent in the net package, so ReadBatch/WriteBatch just loop over
+// ReadFromUDP/WriteToUDP. It still fills up to BatchSize frames per call
+// (draining anything immediately available) so callers see the same
+// batch-shaped API on every platform.
+type scalarBatchUDP struct {
+	conn      *net.UDPConn
+	batchSize int
+}
+
+func newBatchBackend(conn *net.UDPConn) batchBackend {
+	return &scalarBatchUDP{conn: conn, batchSize: DefaultBatchSize}
+}
+
+func (b *scalarBatchUDP) BatchSize() int { return b.batchSize }
+
+func (b *scalarBatchUDP) ReadBatch(ctx context.Context) ([]Frame, []net.Addr, error) {
+	bufPtr := framePool.Get().(*[]byte)
+	defer framePool.Put(bufPtr)
+	buf := *bufPtr
+
+	var frames []Frame
+	var peers []net.Addr
+	for len(frames) < b.batchSize {
+		deadline := 3 * time.Second
+		if len(frames) > 0 {
+			// We already have at least one frame; don't block waiting
+			// for more, just drain whatever's ready without blocking.
+			deadline = time.Millisecond
+		}
+		_ = b.conn.SetReadDeadline(time.Now().Add(deadline))
+		n, from, err := b.conn.ReadFromUDP(buf)
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				if len(frames) > 0 {
+					break
+				}
+				select {
+				case <-ctx.Done():
+					return nil, nil, ctx.Err()
+				default:
+					return nil, nil, nil
+				}
+			}
+			if len(frames) > 0 {
+				break
+			}
+			return nil, nil, err
+		}
+		frame := make(Frame, n)
+		copy(frame, buf[:n])
+		frames = append(frames, frame)
+		peers = append(peers, from)
+	}
+	return frames, peers, nil
+}
+
+func (b *scalarBatchUDP) WriteBatch(frames []Frame, peers []net.Addr) error {
+	for i, f := range frames {
+		raddr, ok := peers[i].(*net.UDPAddr)
+		if !ok {
+			return fmt.Errorf("link: udp WriteBatch needs a *net.UDPAddr peer, got %T", peers[i])
+		}
+		if _, err := b.conn.WriteToUDP(f, raddr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *scalarBatchUDP) Close() error { return nil }
@@ -0,0 +1,50 @@
+package link
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+// TestUDPLinkRoundTrip exercises Open("udp://...") end to end over real
+// loopback sockets: one link writes a frame to another's address, and the
+// receiver's ReadFrame must return the same bytes and report the sender
+// as the peer.
+func TestUDPLinkRoundTrip(t *testing.T) {
+	a, err := Open("udp://127.0.0.1:18301")
+	if err != nil {
+		t.Fatalf("Open a: %v", err)
+	}
+	defer a.Close()
+	b, err := Open("udp://127.0.0.1:18302")
+	if err != nil {
+		t.Fatalf("Open b: %v", err)
+	}
+	defer b.Close()
+
+	want := Frame{0xFD, 0x02, 0, 0, 0, 1, 1, 0, 0, 0, 9, 9, 0x34, 0x12}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	bAddr, err := net.ResolveUDPAddr("udp", "127.0.0.1:18302")
+	if err != nil {
+		t.Fatalf("resolve b addr: %v", err)
+	}
+	if err := a.WriteFrame(want, bAddr); err != nil {
+		t.Fatalf("WriteFrame: %v", err)
+	}
+
+	got, from, err := b.ReadFrame(ctx)
+	if err != nil {
+		t.Fatalf("ReadFrame: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("ReadFrame() = % x, want % x", got, want)
+	}
+	if from == nil {
+		t.Error("ReadFrame() peer = nil, want a's address")
+	}
+}
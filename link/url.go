@@ -0,0 +1,46 @@
+package link
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Open parses a link URL and returns the matching transport:
+//
+//	udp://[host]:port              e.g. udp://:14550
+//	tcp://host:port                 e.g. tcp://127.0.0.1:5760
+//	serial:///dev/ttyACM0?baud=57600
+//
+// The scheme selects the implementation; everything else is
+// transport-specific.
+func Open(rawURL string, opts ...Option) (Link, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("link: parse %q: %w", rawURL, err)
+	}
+
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	switch u.Scheme {
+	case "udp":
+		return newUDPLink(u.Host)
+	case "tcp":
+		return newTCPLink(u.Host, o)
+	case "serial":
+		baud := 57600
+		if b := u.Query().Get("baud"); b != "" {
+			parsed, err := strconv.Atoi(b)
+			if err != nil {
+				return nil, fmt.Errorf("link: bad baud rate %q: %w", b, err)
+			}
+			baud = parsed
+		}
+		return newSerialLink(u.Path, baud, o)
+	default:
+		return nil, fmt.Errorf("link: unsupported scheme %q (want udp, tcp, or serial)", u.Scheme)
+	}
+}
@@ -0,0 +1,29 @@
+package mavlink
+
+// CRC-16/MCRF4XX, the X.25-derived variant MAVLink uses over the frame body
+// plus a per-message CRC_EXTRA seed byte. Polynomial 0x1021, reflected,
+// initial value 0xFFFF, no final XOR.
+
+const crcInit uint16 = 0xFFFF
+
+// crcAccumulate folds one byte into a running CRC the same way the
+// reference mavlink C library's crc_accumulate() does.
+func crcAccumulate(b byte, crc uint16) uint16 {
+	tmp := b ^ byte(crc&0xFF)
+	tmp ^= tmp << 4
+	return (crc >> 8) ^ (uint16(tmp) << 8) ^ (uint16(tmp) << 3) ^ (uint16(tmp) >> 4)
+}
+
+// crcCompute runs crcAccumulate over buf, then seeds the result with the
+// message's CRC_EXTRA byte as the final accumulate step (per the MAVLink v2
+// framing spec). buf must be the frame bytes from the length byte through
+// the end of the payload, i.e. everything between the 0xFD start-of-frame
+// marker and the checksum.
+func crcCompute(buf []byte, crcExtra byte) uint16 {
+	crc := crcInit
+	for _, b := range buf {
+		crc = crcAccumulate(b, crc)
+	}
+	crc = crcAccumulate(crcExtra, crc)
+	return crc
+}
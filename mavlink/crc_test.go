@@ -0,0 +1,37 @@
+package mavlink
+
+import "testing"
+
+// TestCRCComputeKnownAnswer pins crcCompute against a fixed, hand-built
+// HEARTBEAT frame body (sysid=1, compid=1, seq=0, GCS-style heartbeat
+// payload) as a known-answer regression check, so a change to
+// crcAccumulate's bit-twiddling gets caught even if TestRoundTrip's
+// encode/decode symmetry would otherwise mask it.
+func TestCRCComputeKnownAnswer(t *testing.T) {
+	// len=9, incompat=0, compat=0, seq=0, sysid=1, compid=1, msgid=0,0,0,
+	// then the 9-byte HEARTBEAT payload for type=6, autopilot=8,
+	// base_mode=0, system_status=4, mavlink_version=3.
+	buf := []byte{
+		9, 0, 0, 0, 1, 1, 0, 0, 0,
+		0, 0, 0, 0, 6, 8, 0, 4, 3,
+	}
+	const heartbeatCRCExtra = 50
+	got := crcCompute(buf, heartbeatCRCExtra)
+	const want = 0x800A
+	if got != want {
+		t.Errorf("crcCompute(heartbeat) = 0x%04X, want 0x%04X", got, want)
+	}
+}
+
+// TestCRCComputeDiffersOnCRCExtra guards against the most common way to
+// break CRC_EXTRA support: forgetting to fold it in at all, which would
+// make every message's checksum collide with every other message's
+// identical payload regardless of CRC_EXTRA.
+func TestCRCComputeDiffersOnCRCExtra(t *testing.T) {
+	buf := []byte{9, 0, 0, 0, 1, 1, 0, 0, 0, 0, 0, 0, 0, 6, 8, 0, 4, 3}
+	a := crcCompute(buf, 50)
+	b := crcCompute(buf, 51)
+	if a == b {
+		t.Fatalf("crcCompute ignored CRC_EXTRA: got 0x%04X for both 50 and 51", a)
+	}
+}
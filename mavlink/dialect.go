@@ -0,0 +1,46 @@
+package mavlink
+
+import "fmt"
+
+// Message is implemented by every decodable/encodable MAVLink message
+// struct in the dialect. Unmarshal receives the payload already re-padded
+// to the message's registered PayloadLen; Marshal must return exactly
+// PayloadLen bytes (trailing-zero trimming happens in the frame encoder,
+// not here).
+type Message interface {
+	ID() uint32
+	Name() string
+	Marshal() []byte
+	Unmarshal(payload []byte) error
+}
+
+// msgMeta is the per-message entry a code generator would normally emit
+// from the dialect XML: the wire id, its CRC_EXTRA seed, the canonical
+// (untrimmed) payload length, and a constructor for a zero-value message
+// so Decode has something to Unmarshal into.
+type msgMeta struct {
+	Name       string
+	CRCExtra   byte
+	PayloadLen int
+	New        func() Message
+}
+
+var registry = map[uint32]msgMeta{}
+
+// register adds one message to the dialect. Called from init() in
+// messages.go for every message this package knows how to decode; a real
+// code generator would emit one call per <message> in the dialect XML.
+func register(id uint32, name string, crcExtra byte, payloadLen int, new func() Message) {
+	if _, exists := registry[id]; exists {
+		panic(fmt.Sprintf("mavlink: duplicate registration for message id %d", id))
+	}
+	registry[id] = msgMeta{Name: name, CRCExtra: crcExtra, PayloadLen: payloadLen, New: new}
+}
+
+// lookup returns the dialect metadata for msgID, or false if this package
+// doesn't know the message (e.g. it's outside the minimal/common subset
+// this build was generated for).
+func lookup(msgID uint32) (msgMeta, bool) {
+	m, ok := registry[msgID]
+	return m, ok
+}
@@ -0,0 +1,240 @@
+package mavlink
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+)
+
+// Wire-format constants for MAVLink v2 framing.
+const (
+	STX           = 0xFD
+	HeaderLen     = 10 // stx, len, incompat, compat, seq, sysid, compid, msgid(3)
+	ChecksumLen   = 2
+	SignatureLen  = 13 // linkid(1) + timestamp(6) + signature(6)
+	MaxPayloadLen = 255
+	// MaxFrameLen is the largest a single v2 frame can be: header + max
+	// payload + checksum + signature.
+	MaxFrameLen = HeaderLen + MaxPayloadLen + ChecksumLen + SignatureLen
+
+	iflagSigned = 0x01
+)
+
+// Frame is a decoded MAVLink v2 packet: routing header plus the typed
+// Message it carried.
+type Frame struct {
+	Seq    uint8
+	SysID  uint8
+	CompID uint8
+	Msg    Message
+
+	Signed    bool
+	LinkID    uint8
+	Timestamp uint64 // 48-bit signing timestamp, units of 10us since 2015-01-01
+}
+
+// SigningKeyFunc resolves the 32-byte secret key for a signing link, keyed
+// by the sender identity and link id carried in the signature trailer.
+// ok is false if no key is provisioned for that link, which Decode treats
+// as a verification failure rather than silently accepting the frame.
+type SigningKeyFunc func(sysID, compID, linkID uint8) (key [32]byte, ok bool)
+
+// SignParams configures outgoing message signing for Encode.
+type SignParams struct {
+	LinkID    uint8
+	Key       [32]byte
+	Timestamp uint64
+}
+
+type sigTrackKey struct {
+	sysID, compID, linkID uint8
+}
+
+// Codec decodes and encodes frames for one link. It is not safe for
+// concurrent use by multiple goroutines; callers that share a Codec across
+// goroutines must serialize access themselves (mirroring how the rest of
+// this package treats a link as owned by a single reader).
+type Codec struct {
+	keyFunc   SigningKeyFunc
+	lastStamp map[sigTrackKey]uint64
+}
+
+// NewCodec builds a Codec. keyFunc may be nil if this link never needs to
+// verify or produce signed frames; any signed frame received is then
+// rejected with an error.
+func NewCodec(keyFunc SigningKeyFunc) *Codec {
+	return &Codec{keyFunc: keyFunc, lastStamp: make(map[sigTrackKey]uint64)}
+}
+
+// VerifyChecksum reports whether buf looks like a structurally valid,
+// CRC_EXTRA-checksummed frame for a known message id. It does not check
+// signatures or track signing timestamps, so it's safe to call
+// speculatively (e.g. from a stream framer resyncing after corruption)
+// without disturbing a Codec's signing state the way a full Decode would.
+func VerifyChecksum(buf []byte) bool {
+	if len(buf) < HeaderLen+ChecksumLen || buf[0] != STX {
+		return false
+	}
+	payloadLen := int(buf[1])
+	msgID := uint32(buf[7]) | uint32(buf[8])<<8 | uint32(buf[9])<<16
+	signed := buf[2]&iflagSigned != 0
+
+	want := HeaderLen + payloadLen + ChecksumLen
+	if signed {
+		want += SignatureLen
+	}
+	if len(buf) < want {
+		return false
+	}
+
+	meta, ok := lookup(msgID)
+	if !ok {
+		return false
+	}
+
+	crcBuf := make([]byte, 0, HeaderLen-1+payloadLen)
+	crcBuf = append(crcBuf, buf[1:HeaderLen]...)
+	crcBuf = append(crcBuf, buf[HeaderLen:HeaderLen+payloadLen]...)
+	gotCRC := binary.LittleEndian.Uint16(buf[HeaderLen+payloadLen : HeaderLen+payloadLen+ChecksumLen])
+	return gotCRC == crcCompute(crcBuf, meta.CRCExtra)
+}
+
+// Decode parses and verifies a single MAVLink v2 frame from buf, which
+// must contain exactly one frame (no trailing bytes from the next one).
+// It validates the CRC_EXTRA checksum and, if the frame is signed, the
+// SHA-256 signature and per-link monotonic timestamp before returning the
+// decoded Message.
+func (c *Codec) Decode(buf []byte) (*Frame, error) {
+	if len(buf) < HeaderLen+ChecksumLen {
+		return nil, fmt.Errorf("mavlink: frame too short (%d bytes)", len(buf))
+	}
+	if buf[0] != STX {
+		return nil, fmt.Errorf("mavlink: bad start-of-frame byte 0x%02X", buf[0])
+	}
+	payloadLen := int(buf[1])
+	incompat := buf[2]
+	seq := buf[4]
+	sysID := buf[5]
+	compID := buf[6]
+	msgID := uint32(buf[7]) | uint32(buf[8])<<8 | uint32(buf[9])<<16
+	signed := incompat&iflagSigned != 0
+
+	want := HeaderLen + payloadLen + ChecksumLen
+	if signed {
+		want += SignatureLen
+	}
+	if len(buf) < want {
+		return nil, fmt.Errorf("mavlink: short frame: have %d bytes, want %d", len(buf), want)
+	}
+
+	meta, ok := lookup(msgID)
+	if !ok {
+		return nil, fmt.Errorf("mavlink: unknown message id %d", msgID)
+	}
+
+	// The checksum covers exactly what was put on the wire, i.e. the
+	// trimmed payload, not the zero-padded one. Only after it's verified
+	// do we re-pad to the canonical length so field parsing has a fixed
+	// layout to index into.
+	crcBuf := make([]byte, 0, HeaderLen-1+payloadLen)
+	crcBuf = append(crcBuf, buf[1:HeaderLen]...)
+	crcBuf = append(crcBuf, buf[HeaderLen:HeaderLen+payloadLen]...)
+	gotCRC := binary.LittleEndian.Uint16(buf[HeaderLen+payloadLen : HeaderLen+payloadLen+ChecksumLen])
+	wantCRC := crcCompute(crcBuf, meta.CRCExtra)
+	if gotCRC != wantCRC {
+		return nil, fmt.Errorf("mavlink: checksum mismatch for %s: got 0x%04X want 0x%04X", meta.Name, gotCRC, wantCRC)
+	}
+
+	padded := make([]byte, meta.PayloadLen)
+	copy(padded, buf[HeaderLen:HeaderLen+payloadLen])
+
+	f := &Frame{Seq: seq, SysID: sysID, CompID: compID, Signed: signed}
+
+	if signed {
+		sigOff := HeaderLen + payloadLen + ChecksumLen
+		linkID := buf[sigOff]
+		timestamp := uint64(0)
+		for i := 5; i >= 0; i-- {
+			timestamp = timestamp<<8 | uint64(buf[sigOff+1+i])
+		}
+		gotSig := buf[sigOff+7 : sigOff+13]
+
+		if c.keyFunc == nil {
+			return nil, fmt.Errorf("mavlink: signed frame but no signing key configured")
+		}
+		key, ok := c.keyFunc(sysID, compID, linkID)
+		if !ok {
+			return nil, fmt.Errorf("mavlink: no signing key for sysid=%d compid=%d linkid=%d", sysID, compID, linkID)
+		}
+		signedPortion := buf[0 : sigOff+7]
+		sum := sha256.Sum256(append(key[:], signedPortion...))
+		if subtle.ConstantTimeCompare(sum[:6], gotSig) != 1 {
+			return nil, fmt.Errorf("mavlink: signature mismatch from sysid=%d compid=%d linkid=%d", sysID, compID, linkID)
+		}
+
+		track := sigTrackKey{sysID, compID, linkID}
+		if prev, seen := c.lastStamp[track]; seen && timestamp <= prev {
+			return nil, fmt.Errorf("mavlink: non-monotonic signing timestamp from sysid=%d compid=%d linkid=%d", sysID, compID, linkID)
+		}
+		c.lastStamp[track] = timestamp
+
+		f.LinkID = linkID
+		f.Timestamp = timestamp
+	}
+
+	msg := meta.New()
+	if err := msg.Unmarshal(padded); err != nil {
+		return nil, fmt.Errorf("mavlink: decoding %s: %w", meta.Name, err)
+	}
+	f.Msg = msg
+	return f, nil
+}
+
+// Encode serializes msg into a MAVLink v2 frame addressed from
+// (sysID, compID) with sequence number seq, trimming trailing zero bytes
+// from the payload as real implementations do. If sign is non-nil the
+// frame is signed per MAVLINK_IFLAG_SIGNED and sign.Timestamp is recorded
+// so the caller can increment it for the next frame on that link.
+func (c *Codec) Encode(sysID, compID, seq uint8, msg Message, sign *SignParams) ([]byte, error) {
+	meta, ok := lookup(msg.ID())
+	if !ok {
+		return nil, fmt.Errorf("mavlink: message id %d is not registered", msg.ID())
+	}
+	payload := msg.Marshal()
+	if len(payload) != meta.PayloadLen {
+		return nil, fmt.Errorf("mavlink: %s.Marshal() returned %d bytes, want %d", meta.Name, len(payload), meta.PayloadLen)
+	}
+	trimmed := len(payload)
+	for trimmed > 0 && payload[trimmed-1] == 0 {
+		trimmed--
+	}
+	payload = payload[:trimmed]
+
+	incompat := byte(0)
+	if sign != nil {
+		incompat |= iflagSigned
+	}
+
+	buf := make([]byte, 0, MaxFrameLen)
+	buf = append(buf, STX, byte(len(payload)), incompat, 0, seq, sysID, compID)
+	buf = append(buf, byte(msg.ID()), byte(msg.ID()>>8), byte(msg.ID()>>16))
+	buf = append(buf, payload...)
+
+	crcBuf := buf[1:]
+	crc := crcCompute(crcBuf, meta.CRCExtra)
+	buf = append(buf, byte(crc), byte(crc>>8))
+
+	if sign != nil {
+		buf = append(buf, sign.LinkID)
+		ts := sign.Timestamp
+		for i := 0; i < 6; i++ {
+			buf = append(buf, byte(ts))
+			ts >>= 8
+		}
+		sum := sha256.Sum256(append(sign.Key[:], buf...))
+		buf = append(buf, sum[:6]...)
+	}
+
+	return buf, nil
+}
@@ -0,0 +1,192 @@
+package mavlink
+
+import (
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	codec := NewCodec(nil)
+	hb := &Heartbeat{
+		Type:           6,
+		Autopilot:      8,
+		BaseMode:       0x80,
+		SystemStatus:   4,
+		MavlinkVersion: 3,
+		CustomMode:     42,
+	}
+
+	frame, err := codec.Encode(1, 1, 7, hb, nil)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	decoded, err := codec.Decode(frame)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if decoded.SysID != 1 || decoded.CompID != 1 || decoded.Seq != 7 {
+		t.Fatalf("Decode header = sysid=%d compid=%d seq=%d, want 1/1/7", decoded.SysID, decoded.CompID, decoded.Seq)
+	}
+	got, ok := decoded.Msg.(*Heartbeat)
+	if !ok {
+		t.Fatalf("Decode.Msg = %T, want *Heartbeat", decoded.Msg)
+	}
+	if *got != *hb {
+		t.Errorf("round-tripped Heartbeat = %+v, want %+v", *got, *hb)
+	}
+}
+
+// TestRoundTripTrimsTrailingZeros exercises a message whose payload ends
+// in zero bytes (PARAM_SET with a param_id shorter than its 16-byte field
+// and a zero param_type) to confirm Decode's checksum is computed over the same
+// trimmed bytes Encode actually put on the wire, not a zero-padded
+// re-expansion of them.
+func TestRoundTripTrimsTrailingZeros(t *testing.T) {
+	codec := NewCodec(nil)
+	ps := &ParamSet{
+		TargetSystem:    1,
+		TargetComponent: 1,
+		ParamID:         "THR",
+		ParamValue:      3.5,
+		ParamType:       0,
+	}
+
+	frame, err := codec.Encode(255, 190, 1, ps, nil)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	decoded, err := codec.Decode(frame)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	got, ok := decoded.Msg.(*ParamSet)
+	if !ok {
+		t.Fatalf("Decode.Msg = %T, want *ParamSet", decoded.Msg)
+	}
+	if *got != *ps {
+		t.Errorf("round-tripped ParamSet = %+v, want %+v", *got, *ps)
+	}
+}
+
+func TestDecodeRejectsBadChecksum(t *testing.T) {
+	codec := NewCodec(nil)
+	frame, err := codec.Encode(1, 1, 0, &Heartbeat{Type: 6}, nil)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	frame[len(frame)-1] ^= 0xFF // corrupt the checksum
+
+	if _, err := codec.Decode(frame); err == nil {
+		t.Fatal("Decode accepted a frame with a corrupted checksum")
+	}
+}
+
+func TestDecodeRejectsUnknownMessage(t *testing.T) {
+	codec := NewCodec(nil)
+	frame, err := codec.Encode(1, 1, 0, &Heartbeat{Type: 6}, nil)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	frame[7], frame[8], frame[9] = 0xFF, 0xFF, 0xFF // bogus msgid
+
+	if _, err := codec.Decode(frame); err == nil {
+		t.Fatal("Decode accepted a frame with an unregistered message id")
+	}
+}
+
+func TestSigning(t *testing.T) {
+	var key [32]byte
+	copy(key[:], "test-signing-key")
+	keyFunc := func(sysID, compID, linkID uint8) ([32]byte, bool) {
+		return key, true
+	}
+
+	senderCodec := NewCodec(keyFunc)
+	receiverCodec := NewCodec(keyFunc)
+	sign := &SignParams{LinkID: 3, Key: key, Timestamp: 1000}
+
+	frame, err := senderCodec.Encode(1, 1, 0, &Heartbeat{Type: 6}, sign)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	decoded, err := receiverCodec.Decode(frame)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if !decoded.Signed || decoded.LinkID != 3 || decoded.Timestamp != 1000 {
+		t.Fatalf("Decode signature fields = signed=%v linkid=%d timestamp=%d, want true/3/1000",
+			decoded.Signed, decoded.LinkID, decoded.Timestamp)
+	}
+}
+
+// TestSigningRejectsReplayedTimestamp confirms Decode enforces the
+// strictly-increasing per-(sysid,compid,linkid) timestamp MAVLink's
+// signing scheme relies on to reject replayed frames.
+func TestSigningRejectsReplayedTimestamp(t *testing.T) {
+	var key [32]byte
+	copy(key[:], "test-signing-key")
+	keyFunc := func(sysID, compID, linkID uint8) ([32]byte, bool) { return key, true }
+
+	senderCodec := NewCodec(keyFunc)
+	receiverCodec := NewCodec(keyFunc)
+	sign := &SignParams{LinkID: 3, Key: key, Timestamp: 1000}
+
+	frame, err := senderCodec.Encode(1, 1, 0, &Heartbeat{Type: 6}, sign)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if _, err := receiverCodec.Decode(frame); err != nil {
+		t.Fatalf("first Decode: %v", err)
+	}
+
+	// Re-send the exact same (replayed) frame, and a second frame at the
+	// same timestamp — both must be rejected.
+	if _, err := receiverCodec.Decode(frame); err == nil {
+		t.Fatal("Decode accepted a replayed frame")
+	}
+
+	frame2, err := senderCodec.Encode(1, 1, 1, &Heartbeat{Type: 6}, sign) // same Timestamp: 1000
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if _, err := receiverCodec.Decode(frame2); err == nil {
+		t.Fatal("Decode accepted a non-increasing signing timestamp")
+	}
+}
+
+func TestSigningRejectsBadSignature(t *testing.T) {
+	var key [32]byte
+	copy(key[:], "test-signing-key")
+	keyFunc := func(sysID, compID, linkID uint8) ([32]byte, bool) { return key, true }
+
+	codec := NewCodec(keyFunc)
+	sign := &SignParams{LinkID: 3, Key: key, Timestamp: 1000}
+	frame, err := codec.Encode(1, 1, 0, &Heartbeat{Type: 6}, sign)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	frame[len(frame)-1] ^= 0xFF // corrupt the signature, not the checksum
+
+	if _, err := codec.Decode(frame); err == nil {
+		t.Fatal("Decode accepted a frame with a corrupted signature")
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	codec := NewCodec(nil)
+	frame, err := codec.Encode(1, 1, 0, &Heartbeat{Type: 6}, nil)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if !VerifyChecksum(frame) {
+		t.Error("VerifyChecksum rejected a valid frame")
+	}
+
+	bad := append([]byte(nil), frame...)
+	bad[len(bad)-1] ^= 0xFF
+	if VerifyChecksum(bad) {
+		t.Error("VerifyChecksum accepted a corrupted frame")
+	}
+}
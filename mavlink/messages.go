@@ -0,0 +1,550 @@
+package mavlink
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// Message ids for the subset of the common/minimal dialect this package
+// understands. A real build would generate these (and the structs below)
+// from the dialect XML; here they're hand-transcribed from it.
+const (
+	MsgIDHeartbeat         = 0
+	MsgIDSysStatus         = 1
+	MsgIDAttitude          = 30
+	MsgIDMissionCount      = 44
+	MsgIDMissionAck        = 47
+	MsgIDMissionRequestInt = 51
+	MsgIDParamRequestRead  = 20
+	MsgIDParamRequestList  = 21
+	MsgIDParamValue        = 22
+	MsgIDParamSet          = 23
+	MsgIDMissionItemInt    = 73
+	MsgIDCommandLong       = 76
+	MsgIDCommandAck        = 77
+	MsgIDHighresIMU        = 105
+)
+
+func init() {
+	register(MsgIDHeartbeat, "HEARTBEAT", 50, 9, func() Message { return &Heartbeat{} })
+	register(MsgIDSysStatus, "SYS_STATUS", 124, 31, func() Message { return &SysStatus{} })
+	register(MsgIDParamRequestRead, "PARAM_REQUEST_READ", 214, 20, func() Message { return &ParamRequestRead{} })
+	register(MsgIDParamRequestList, "PARAM_REQUEST_LIST", 159, 2, func() Message { return &ParamRequestList{} })
+	register(MsgIDParamValue, "PARAM_VALUE", 220, 25, func() Message { return &ParamValue{} })
+	register(MsgIDParamSet, "PARAM_SET", 168, 23, func() Message { return &ParamSet{} })
+	register(MsgIDCommandLong, "COMMAND_LONG", 152, 33, func() Message { return &CommandLong{} })
+	register(MsgIDCommandAck, "COMMAND_ACK", 143, 10, func() Message { return &CommandAck{} })
+	register(MsgIDMissionCount, "MISSION_COUNT", 221, 4, func() Message { return &MissionCount{} })
+	register(MsgIDMissionRequestInt, "MISSION_REQUEST_INT", 196, 4, func() Message { return &MissionRequestInt{} })
+	register(MsgIDMissionItemInt, "MISSION_ITEM_INT", 38, 38, func() Message { return &MissionItemInt{} })
+	register(MsgIDMissionAck, "MISSION_ACK", 153, 3, func() Message { return &MissionAck{} })
+	register(MsgIDAttitude, "ATTITUDE", 39, 28, func() Message { return &Attitude{} })
+	register(MsgIDHighresIMU, "HIGHRES_IMU", 93, 62, func() Message { return &HighresIMU{} })
+}
+
+func putFloat32(buf []byte, off int, v float32) {
+	binary.LittleEndian.PutUint32(buf[off:], math.Float32bits(v))
+}
+
+func getFloat32(buf []byte, off int) float32 {
+	return math.Float32frombits(binary.LittleEndian.Uint32(buf[off:]))
+}
+
+// putCharN writes s into an n-byte fixed field, null-padding any remainder.
+func putCharN(buf []byte, s string, n int) {
+	copy(buf[:n], s)
+}
+
+// getCharN reads an n-byte fixed char field, trimming the trailing NULs
+// MAVLink uses to pad short strings.
+func getCharN(buf []byte, n int) string {
+	end := 0
+	for end < n && buf[end] != 0 {
+		end++
+	}
+	return string(buf[:end])
+}
+
+// Heartbeat is MAVLINK_MSG_ID_HEARTBEAT (0).
+type Heartbeat struct {
+	CustomMode     uint32
+	Type           uint8
+	Autopilot      uint8
+	BaseMode       uint8
+	SystemStatus   uint8
+	MavlinkVersion uint8
+}
+
+func (m *Heartbeat) ID() uint32   { return MsgIDHeartbeat }
+func (m *Heartbeat) Name() string { return "HEARTBEAT" }
+func (m *Heartbeat) Armed() bool  { return m.BaseMode&0x80 != 0 }
+
+func (m *Heartbeat) Marshal() []byte {
+	buf := make([]byte, 9)
+	binary.LittleEndian.PutUint32(buf[0:], m.CustomMode)
+	buf[4] = m.Type
+	buf[5] = m.Autopilot
+	buf[6] = m.BaseMode
+	buf[7] = m.SystemStatus
+	buf[8] = m.MavlinkVersion
+	return buf
+}
+
+func (m *Heartbeat) Unmarshal(p []byte) error {
+	m.CustomMode = binary.LittleEndian.Uint32(p[0:])
+	m.Type = p[4]
+	m.Autopilot = p[5]
+	m.BaseMode = p[6]
+	m.SystemStatus = p[7]
+	m.MavlinkVersion = p[8]
+	return nil
+}
+
+// SysStatus is MAVLINK_MSG_ID_SYS_STATUS (1).
+type SysStatus struct {
+	OnboardControlSensorsPresent uint32
+	OnboardControlSensorsEnabled uint32
+	OnboardControlSensorsHealth  uint32
+	Load                         uint16
+	VoltageBattery               uint16
+	CurrentBattery               int16
+	DropRateComm                 uint16
+	ErrorsComm                   uint16
+	ErrorsCount1                 uint16
+	ErrorsCount2                 uint16
+	ErrorsCount3                 uint16
+	ErrorsCount4                 uint16
+	BatteryRemaining             int8
+}
+
+func (m *SysStatus) ID() uint32   { return MsgIDSysStatus }
+func (m *SysStatus) Name() string { return "SYS_STATUS" }
+
+func (m *SysStatus) Marshal() []byte {
+	buf := make([]byte, 31)
+	binary.LittleEndian.PutUint32(buf[0:], m.OnboardControlSensorsPresent)
+	binary.LittleEndian.PutUint32(buf[4:], m.OnboardControlSensorsEnabled)
+	binary.LittleEndian.PutUint32(buf[8:], m.OnboardControlSensorsHealth)
+	binary.LittleEndian.PutUint16(buf[12:], m.Load)
+	binary.LittleEndian.PutUint16(buf[14:], m.VoltageBattery)
+	binary.LittleEndian.PutUint16(buf[16:], uint16(m.CurrentBattery))
+	binary.LittleEndian.PutUint16(buf[18:], m.DropRateComm)
+	binary.LittleEndian.PutUint16(buf[20:], m.ErrorsComm)
+	binary.LittleEndian.PutUint16(buf[22:], m.ErrorsCount1)
+	binary.LittleEndian.PutUint16(buf[24:], m.ErrorsCount2)
+	binary.LittleEndian.PutUint16(buf[26:], m.ErrorsCount3)
+	binary.LittleEndian.PutUint16(buf[28:], m.ErrorsCount4)
+	buf[30] = byte(m.BatteryRemaining)
+	return buf
+}
+
+func (m *SysStatus) Unmarshal(p []byte) error {
+	m.OnboardControlSensorsPresent = binary.LittleEndian.Uint32(p[0:])
+	m.OnboardControlSensorsEnabled = binary.LittleEndian.Uint32(p[4:])
+	m.OnboardControlSensorsHealth = binary.LittleEndian.Uint32(p[8:])
+	m.Load = binary.LittleEndian.Uint16(p[12:])
+	m.VoltageBattery = binary.LittleEndian.Uint16(p[14:])
+	m.CurrentBattery = int16(binary.LittleEndian.Uint16(p[16:]))
+	m.DropRateComm = binary.LittleEndian.Uint16(p[18:])
+	m.ErrorsComm = binary.LittleEndian.Uint16(p[20:])
+	m.ErrorsCount1 = binary.LittleEndian.Uint16(p[22:])
+	m.ErrorsCount2 = binary.LittleEndian.Uint16(p[24:])
+	m.ErrorsCount3 = binary.LittleEndian.Uint16(p[26:])
+	m.ErrorsCount4 = binary.LittleEndian.Uint16(p[28:])
+	m.BatteryRemaining = int8(p[30])
+	return nil
+}
+
+// ParamRequestRead is MAVLINK_MSG_ID_PARAM_REQUEST_READ (20): request one
+// parameter, by ParamID if set or by ParamIndex (ParamID empty, index
+// >= 0) otherwise. Used to re-request a specific index after a gap in
+// a PARAM_REQUEST_LIST stream rather than re-pulling everything.
+type ParamRequestRead struct {
+	ParamIndex      int16
+	TargetSystem    uint8
+	TargetComponent uint8
+	ParamID         string
+}
+
+func (m *ParamRequestRead) ID() uint32   { return MsgIDParamRequestRead }
+func (m *ParamRequestRead) Name() string { return "PARAM_REQUEST_READ" }
+
+func (m *ParamRequestRead) Marshal() []byte {
+	buf := make([]byte, 20)
+	binary.LittleEndian.PutUint16(buf[0:], uint16(m.ParamIndex))
+	buf[2] = m.TargetSystem
+	buf[3] = m.TargetComponent
+	putCharN(buf[4:20], m.ParamID, 16)
+	return buf
+}
+
+func (m *ParamRequestRead) Unmarshal(p []byte) error {
+	m.ParamIndex = int16(binary.LittleEndian.Uint16(p[0:]))
+	m.TargetSystem = p[2]
+	m.TargetComponent = p[3]
+	m.ParamID = getCharN(p[4:20], 16)
+	return nil
+}
+
+// ParamRequestList is MAVLINK_MSG_ID_PARAM_REQUEST_LIST (21): request a
+// full streamed dump of the target's parameters.
+type ParamRequestList struct {
+	TargetSystem    uint8
+	TargetComponent uint8
+}
+
+func (m *ParamRequestList) ID() uint32   { return MsgIDParamRequestList }
+func (m *ParamRequestList) Name() string { return "PARAM_REQUEST_LIST" }
+
+func (m *ParamRequestList) Marshal() []byte {
+	return []byte{m.TargetSystem, m.TargetComponent}
+}
+
+func (m *ParamRequestList) Unmarshal(p []byte) error {
+	m.TargetSystem = p[0]
+	m.TargetComponent = p[1]
+	return nil
+}
+
+// ParamValue is MAVLINK_MSG_ID_PARAM_VALUE (22), sent in response to
+// PARAM_REQUEST_LIST/PARAM_REQUEST_READ and to echo a PARAM_SET.
+type ParamValue struct {
+	ParamValue float32
+	ParamCount uint16
+	ParamIndex uint16
+	ParamID    string // up to 16 chars, NUL-padded on the wire
+	ParamType  uint8
+}
+
+func (m *ParamValue) ID() uint32   { return MsgIDParamValue }
+func (m *ParamValue) Name() string { return "PARAM_VALUE" }
+
+func (m *ParamValue) Marshal() []byte {
+	buf := make([]byte, 25)
+	putFloat32(buf, 0, m.ParamValue)
+	binary.LittleEndian.PutUint16(buf[4:], m.ParamCount)
+	binary.LittleEndian.PutUint16(buf[6:], m.ParamIndex)
+	putCharN(buf[8:24], m.ParamID, 16)
+	buf[24] = m.ParamType
+	return buf
+}
+
+func (m *ParamValue) Unmarshal(p []byte) error {
+	m.ParamValue = getFloat32(p, 0)
+	m.ParamCount = binary.LittleEndian.Uint16(p[4:])
+	m.ParamIndex = binary.LittleEndian.Uint16(p[6:])
+	m.ParamID = getCharN(p[8:24], 16)
+	m.ParamType = p[24]
+	return nil
+}
+
+// ParamSet is MAVLINK_MSG_ID_PARAM_SET (23): request the target adopt a
+// new parameter value; the target must echo it back as a PARAM_VALUE.
+type ParamSet struct {
+	ParamValue      float32
+	TargetSystem    uint8
+	TargetComponent uint8
+	ParamID         string
+	ParamType       uint8
+}
+
+func (m *ParamSet) ID() uint32   { return MsgIDParamSet }
+func (m *ParamSet) Name() string { return "PARAM_SET" }
+
+func (m *ParamSet) Marshal() []byte {
+	buf := make([]byte, 23)
+	putFloat32(buf, 0, m.ParamValue)
+	buf[4] = m.TargetSystem
+	buf[5] = m.TargetComponent
+	putCharN(buf[6:22], m.ParamID, 16)
+	buf[22] = m.ParamType
+	return buf
+}
+
+func (m *ParamSet) Unmarshal(p []byte) error {
+	m.ParamValue = getFloat32(p, 0)
+	m.TargetSystem = p[4]
+	m.TargetComponent = p[5]
+	m.ParamID = getCharN(p[6:22], 16)
+	m.ParamType = p[22]
+	return nil
+}
+
+// CommandLong is MAVLINK_MSG_ID_COMMAND_LONG (76): a MAV_CMD invocation
+// with up to 7 float parameters.
+type CommandLong struct {
+	Param1, Param2, Param3, Param4, Param5, Param6, Param7 float32
+	Command                                                uint16
+	TargetSystem                                           uint8
+	TargetComponent                                        uint8
+	Confirmation                                           uint8
+}
+
+func (m *CommandLong) ID() uint32   { return MsgIDCommandLong }
+func (m *CommandLong) Name() string { return "COMMAND_LONG" }
+
+func (m *CommandLong) Marshal() []byte {
+	buf := make([]byte, 33)
+	putFloat32(buf, 0, m.Param1)
+	putFloat32(buf, 4, m.Param2)
+	putFloat32(buf, 8, m.Param3)
+	putFloat32(buf, 12, m.Param4)
+	putFloat32(buf, 16, m.Param5)
+	putFloat32(buf, 20, m.Param6)
+	putFloat32(buf, 24, m.Param7)
+	binary.LittleEndian.PutUint16(buf[28:], m.Command)
+	buf[30] = m.TargetSystem
+	buf[31] = m.TargetComponent
+	buf[32] = m.Confirmation
+	return buf
+}
+
+func (m *CommandLong) Unmarshal(p []byte) error {
+	m.Param1 = getFloat32(p, 0)
+	m.Param2 = getFloat32(p, 4)
+	m.Param3 = getFloat32(p, 8)
+	m.Param4 = getFloat32(p, 12)
+	m.Param5 = getFloat32(p, 16)
+	m.Param6 = getFloat32(p, 20)
+	m.Param7 = getFloat32(p, 24)
+	m.Command = binary.LittleEndian.Uint16(p[28:])
+	m.TargetSystem = p[30]
+	m.TargetComponent = p[31]
+	m.Confirmation = p[32]
+	return nil
+}
+
+// CommandAck is MAVLINK_MSG_ID_COMMAND_ACK (77): the typed reply to a
+// COMMAND_LONG/COMMAND_INT.
+type CommandAck struct {
+	Command         uint16
+	Result          uint8
+	Progress        uint8
+	ResultParam2    int32
+	TargetSystem    uint8
+	TargetComponent uint8
+}
+
+func (m *CommandAck) ID() uint32   { return MsgIDCommandAck }
+func (m *CommandAck) Name() string { return "COMMAND_ACK" }
+
+func (m *CommandAck) Marshal() []byte {
+	buf := make([]byte, 10)
+	binary.LittleEndian.PutUint16(buf[0:], m.Command)
+	buf[2] = m.Result
+	buf[3] = m.Progress
+	binary.LittleEndian.PutUint32(buf[4:], uint32(m.ResultParam2))
+	buf[8] = m.TargetSystem
+	buf[9] = m.TargetComponent
+	return buf
+}
+
+func (m *CommandAck) Unmarshal(p []byte) error {
+	m.Command = binary.LittleEndian.Uint16(p[0:])
+	m.Result = p[2]
+	m.Progress = p[3]
+	m.ResultParam2 = int32(binary.LittleEndian.Uint32(p[4:]))
+	m.TargetSystem = p[8]
+	m.TargetComponent = p[9]
+	return nil
+}
+
+// MissionCount is MAVLINK_MSG_ID_MISSION_COUNT (44): announces how many
+// MISSION_ITEM_INTs the sender is about to upload (or the receiver should
+// expect to download).
+type MissionCount struct {
+	Count           uint16
+	TargetSystem    uint8
+	TargetComponent uint8
+}
+
+func (m *MissionCount) ID() uint32   { return MsgIDMissionCount }
+func (m *MissionCount) Name() string { return "MISSION_COUNT" }
+
+func (m *MissionCount) Marshal() []byte {
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint16(buf[0:], m.Count)
+	buf[2] = m.TargetSystem
+	buf[3] = m.TargetComponent
+	return buf
+}
+
+func (m *MissionCount) Unmarshal(p []byte) error {
+	m.Count = binary.LittleEndian.Uint16(p[0:])
+	m.TargetSystem = p[2]
+	m.TargetComponent = p[3]
+	return nil
+}
+
+// MissionRequestInt is MAVLINK_MSG_ID_MISSION_REQUEST_INT (51): the
+// receiver pulling one item of an in-progress mission transfer.
+type MissionRequestInt struct {
+	Seq             uint16
+	TargetSystem    uint8
+	TargetComponent uint8
+}
+
+func (m *MissionRequestInt) ID() uint32   { return MsgIDMissionRequestInt }
+func (m *MissionRequestInt) Name() string { return "MISSION_REQUEST_INT" }
+
+func (m *MissionRequestInt) Marshal() []byte {
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint16(buf[0:], m.Seq)
+	buf[2] = m.TargetSystem
+	buf[3] = m.TargetComponent
+	return buf
+}
+
+func (m *MissionRequestInt) Unmarshal(p []byte) error {
+	m.Seq = binary.LittleEndian.Uint16(p[0:])
+	m.TargetSystem = p[2]
+	m.TargetComponent = p[3]
+	return nil
+}
+
+// MissionItemInt is MAVLINK_MSG_ID_MISSION_ITEM_INT (73): one waypoint,
+// with lat/lon carried as 1e7-scaled integers for precision.
+type MissionItemInt struct {
+	Param1, Param2, Param3, Param4 float32
+	X, Y                           int32
+	Z                              float32
+	Seq                            uint16
+	Command                        uint16
+	TargetSystem                   uint8
+	TargetComponent                uint8
+	Frame                          uint8
+	Current                        uint8
+	Autocontinue                   uint8
+}
+
+func (m *MissionItemInt) ID() uint32   { return MsgIDMissionItemInt }
+func (m *MissionItemInt) Name() string { return "MISSION_ITEM_INT" }
+
+func (m *MissionItemInt) Marshal() []byte {
+	buf := make([]byte, 38)
+	putFloat32(buf, 0, m.Param1)
+	putFloat32(buf, 4, m.Param2)
+	putFloat32(buf, 8, m.Param3)
+	putFloat32(buf, 12, m.Param4)
+	binary.LittleEndian.PutUint32(buf[16:], uint32(m.X))
+	binary.LittleEndian.PutUint32(buf[20:], uint32(m.Y))
+	putFloat32(buf, 24, m.Z)
+	binary.LittleEndian.PutUint16(buf[28:], m.Seq)
+	binary.LittleEndian.PutUint16(buf[30:], m.Command)
+	buf[32] = m.TargetSystem
+	buf[33] = m.TargetComponent
+	buf[34] = m.Frame
+	buf[35] = m.Current
+	buf[36] = m.Autocontinue
+	return buf
+}
+
+func (m *MissionItemInt) Unmarshal(p []byte) error {
+	m.Param1 = getFloat32(p, 0)
+	m.Param2 = getFloat32(p, 4)
+	m.Param3 = getFloat32(p, 8)
+	m.Param4 = getFloat32(p, 12)
+	m.X = int32(binary.LittleEndian.Uint32(p[16:]))
+	m.Y = int32(binary.LittleEndian.Uint32(p[20:]))
+	m.Z = getFloat32(p, 24)
+	m.Seq = binary.LittleEndian.Uint16(p[28:])
+	m.Command = binary.LittleEndian.Uint16(p[30:])
+	m.TargetSystem = p[32]
+	m.TargetComponent = p[33]
+	m.Frame = p[34]
+	m.Current = p[35]
+	m.Autocontinue = p[36]
+	return nil
+}
+
+// MissionAck is MAVLINK_MSG_ID_MISSION_ACK (47): the final handshake
+// message for a mission upload or download.
+type MissionAck struct {
+	TargetSystem    uint8
+	TargetComponent uint8
+	Type            uint8
+}
+
+func (m *MissionAck) ID() uint32   { return MsgIDMissionAck }
+func (m *MissionAck) Name() string { return "MISSION_ACK" }
+
+func (m *MissionAck) Marshal() []byte {
+	return []byte{m.TargetSystem, m.TargetComponent, m.Type}
+}
+
+func (m *MissionAck) Unmarshal(p []byte) error {
+	m.TargetSystem = p[0]
+	m.TargetComponent = p[1]
+	m.Type = p[2]
+	return nil
+}
+
+// Attitude is MAVLINK_MSG_ID_ATTITUDE (30): vehicle orientation and rates.
+type Attitude struct {
+	TimeBootMs                      uint32
+	Roll, Pitch, Yaw                float32
+	RollSpeed, PitchSpeed, YawSpeed float32
+}
+
+func (m *Attitude) ID() uint32   { return MsgIDAttitude }
+func (m *Attitude) Name() string { return "ATTITUDE" }
+
+func (m *Attitude) Marshal() []byte {
+	buf := make([]byte, 28)
+	binary.LittleEndian.PutUint32(buf[0:], m.TimeBootMs)
+	putFloat32(buf, 4, m.Roll)
+	putFloat32(buf, 8, m.Pitch)
+	putFloat32(buf, 12, m.Yaw)
+	putFloat32(buf, 16, m.RollSpeed)
+	putFloat32(buf, 20, m.PitchSpeed)
+	putFloat32(buf, 24, m.YawSpeed)
+	return buf
+}
+
+func (m *Attitude) Unmarshal(p []byte) error {
+	m.TimeBootMs = binary.LittleEndian.Uint32(p[0:])
+	m.Roll = getFloat32(p, 4)
+	m.Pitch = getFloat32(p, 8)
+	m.Yaw = getFloat32(p, 12)
+	m.RollSpeed = getFloat32(p, 16)
+	m.PitchSpeed = getFloat32(p, 20)
+	m.YawSpeed = getFloat32(p, 24)
+	return nil
+}
+
+// HighresIMU is MAVLINK_MSG_ID_HIGHRES_IMU (105): raw high-rate IMU data,
+// the kind of message that makes the batched I/O path in package link
+// worth having.
+type HighresIMU struct {
+	TimeUsec                               uint64
+	Xacc, Yacc, Zacc                       float32
+	Xgyro, Ygyro, Zgyro                    float32
+	Xmag, Ymag, Zmag                       float32
+	AbsPressure, DiffPressure, PressureAlt float32
+	Temperature                            float32
+	FieldsUpdated                          uint16
+}
+
+func (m *HighresIMU) ID() uint32   { return MsgIDHighresIMU }
+func (m *HighresIMU) Name() string { return "HIGHRES_IMU" }
+
+func (m *HighresIMU) Marshal() []byte {
+	buf := make([]byte, 62)
+	binary.LittleEndian.PutUint64(buf[0:], m.TimeUsec)
+	vals := []float32{m.Xacc, m.Yacc, m.Zacc, m.Xgyro, m.Ygyro, m.Zgyro, m.Xmag, m.Ymag, m.Zmag, m.AbsPressure, m.DiffPressure, m.PressureAlt, m.Temperature}
+	for i, v := range vals {
+		putFloat32(buf, 8+i*4, v)
+	}
+	binary.LittleEndian.PutUint16(buf[60:], m.FieldsUpdated)
+	return buf
+}
+
+func (m *HighresIMU) Unmarshal(p []byte) error {
+	m.TimeUsec = binary.LittleEndian.Uint64(p[0:])
+	vals := [13]*float32{&m.Xacc, &m.Yacc, &m.Zacc, &m.Xgyro, &m.Ygyro, &m.Zgyro, &m.Xmag, &m.Ymag, &m.Zmag, &m.AbsPressure, &m.DiffPressure, &m.PressureAlt, &m.Temperature}
+	for i, ptr := range vals {
+		*ptr = getFloat32(p, 8+i*4)
+	}
+	m.FieldsUpdated = binary.LittleEndian.Uint16(p[60:])
+	return nil
+}
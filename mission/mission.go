@@ -0,0 +1,172 @@
+// Package mission implements the MAVLink mission upload handshake:
+// MISSION_COUNT, followed by a MISSION_REQUEST_INT/MISSION_ITEM_INT
+// exchange per waypoint, finished by a MISSION_ACK.
+package mission
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/joseph-m-valdez/aetherforge/conn"
+	"github.com/joseph-m-valdez/aetherforge/mavlink"
+)
+
+// Item is one waypoint in a mission, in MISSION_ITEM_INT's 1e7-scaled
+// integer lat/lon form.
+type Item struct {
+	Frame        uint8
+	Command      uint16
+	Current      uint8
+	Autocontinue uint8
+	Param1       float32
+	Param2       float32
+	Param3       float32
+	Param4       float32
+	Lat, Lon     int32 // degrees * 1e7
+	Alt          float32
+}
+
+const (
+	itemTimeout  = 3 * time.Second
+	maxRetries   = 3
+	mavMissionOK = 0 // MAV_MISSION_ACCEPTED
+)
+
+// Uploader drives a mission upload against one target. Like the other
+// protocol clients in this module, it doesn't read from a link itself —
+// feed it every decoded frame via HandleFrame.
+type Uploader struct {
+	conn   *conn.Conn
+	peer   net.Addr
+	sysID  uint8
+	compID uint8
+
+	mu        sync.Mutex
+	requestCh chan uint16
+	ackCh     chan *mavlink.MissionAck
+}
+
+// NewUploader builds an Uploader targeting (sysID, compID) over c,
+// sending to peer.
+func NewUploader(c *conn.Conn, peer net.Addr, sysID, compID uint8) *Uploader {
+	return &Uploader{
+		conn:   c,
+		peer:   peer,
+		sysID:  sysID,
+		compID: compID,
+	}
+}
+
+// channels returns the request/ack channels the in-progress attempt (if
+// any) is reading from.
+func (u *Uploader) channels() (chan uint16, chan *mavlink.MissionAck) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.requestCh, u.ackCh
+}
+
+// resetChannels swaps in fresh request/ack channels for a new upload
+// attempt, so a MISSION_REQUEST_INT or MISSION_ACK that arrives late for
+// a previous, timed-out attempt can't be misattributed to this one.
+func (u *Uploader) resetChannels() (chan uint16, chan *mavlink.MissionAck) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.requestCh = make(chan uint16, 8)
+	u.ackCh = make(chan *mavlink.MissionAck, 1)
+	return u.requestCh, u.ackCh
+}
+
+// HandleFrame delivers f to the upload in progress if it's a
+// MISSION_REQUEST_INT or MISSION_ACK, returning whether it was either.
+func (u *Uploader) HandleFrame(f *mavlink.Frame) bool {
+	requestCh, ackCh := u.channels()
+	switch m := f.Msg.(type) {
+	case *mavlink.MissionRequestInt:
+		select {
+		case requestCh <- m.Seq:
+		default:
+		}
+		return true
+	case *mavlink.MissionAck:
+		select {
+		case ackCh <- m:
+		default:
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// Upload sends MISSION_COUNT, answers every MISSION_REQUEST_INT with the
+// matching MISSION_ITEM_INT, and waits for the final MISSION_ACK. It
+// runs the whole handshake up to maxRetries times in total, starting
+// over after a failed attempt.
+func (u *Uploader) Upload(ctx context.Context, items []Item) error {
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		err := u.uploadOnce(ctx, items)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if attempt == maxRetries-1 {
+			return err
+		}
+	}
+	return fmt.Errorf("mission: upload failed after %d attempts", maxRetries)
+}
+
+func (u *Uploader) uploadOnce(ctx context.Context, items []Item) error {
+	requestCh, ackCh := u.resetChannels()
+
+	count := &mavlink.MissionCount{Count: uint16(len(items)), TargetSystem: u.sysID, TargetComponent: u.compID}
+	if err := u.conn.Send(u.peer, count); err != nil {
+		return err
+	}
+
+	sent := make(map[uint16]bool, len(items))
+	for len(sent) < len(items) {
+		select {
+		case seq := <-requestCh:
+			if int(seq) >= len(items) {
+				continue
+			}
+			if sent[seq] {
+				continue
+			}
+			item := items[seq]
+			msg := &mavlink.MissionItemInt{
+				Param1: item.Param1, Param2: item.Param2, Param3: item.Param3, Param4: item.Param4,
+				X: item.Lat, Y: item.Lon, Z: item.Alt,
+				Seq: seq, Command: item.Command,
+				TargetSystem: u.sysID, TargetComponent: u.compID,
+				Frame: item.Frame, Current: item.Current, Autocontinue: item.Autocontinue,
+			}
+			if err := u.conn.Send(u.peer, msg); err != nil {
+				return err
+			}
+			sent[seq] = true
+		case <-time.After(itemTimeout):
+			return fmt.Errorf("mission: timed out waiting for MISSION_REQUEST_INT (%d/%d sent)", len(sent), len(items))
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	select {
+	case ack := <-ackCh:
+		if ack.Type != mavMissionOK {
+			return fmt.Errorf("mission: upload rejected, MAV_MISSION_RESULT=%d", ack.Type)
+		}
+		return nil
+	case <-time.After(itemTimeout):
+		return fmt.Errorf("mission: timed out waiting for MISSION_ACK")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
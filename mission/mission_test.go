@@ -0,0 +1,86 @@
+package mission
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/joseph-m-valdez/aetherforge/conn"
+	"github.com/joseph-m-valdez/aetherforge/link"
+	"github.com/joseph-m-valdez/aetherforge/mavlink"
+)
+
+// TestUploadOnceStaleAckNotMisattributed reproduces the scenario where a
+// MISSION_ACK meant for a timed-out attempt arrives just before the next
+// attempt starts. Without resetChannels, that stale ack sits buffered in
+// the shared ackCh and gets picked up by the next attempt's final select
+// as if it had just confirmed that attempt's own upload.
+func TestUploadOnceStaleAckNotMisattributed(t *testing.T) {
+	gcsLink, err := link.Open("udp://127.0.0.1:18001")
+	if err != nil {
+		t.Fatalf("link.Open gcs: %v", err)
+	}
+	defer gcsLink.Close()
+	vehLink, err := link.Open("udp://127.0.0.1:18002")
+	if err != nil {
+		t.Fatalf("link.Open veh: %v", err)
+	}
+	defer vehLink.Close()
+
+	gcsConn := conn.New(gcsLink, mavlink.NewCodec(nil), 255, 190)
+	peer := &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 18002}
+	u := NewUploader(gcsConn, peer, 1, 1)
+	items := []Item{{Command: 16, Lat: 1, Lon: 2, Alt: 10}}
+
+	runCtx, runCancel := context.WithCancel(context.Background())
+	defer runCancel()
+	go func() {
+		for {
+			f, _, err := gcsConn.ReadFrame(runCtx)
+			if err != nil {
+				return
+			}
+			u.HandleFrame(f)
+		}
+	}()
+
+	// Attempt 1: nothing is listening on the vehicle side yet, so this
+	// times out waiting for MISSION_REQUEST_INT.
+	attempt1Ctx, cancel1 := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel1()
+	if err := u.uploadOnce(attempt1Ctx, items); err == nil {
+		t.Fatal("attempt 1 unexpectedly succeeded with no responder")
+	}
+
+	// A MISSION_ACK for attempt 1 arrives late, after it already gave up.
+	// It lands in attempt 1's (still current, not yet reset) ackCh.
+	u.HandleFrame(&mavlink.Frame{Msg: &mavlink.MissionAck{Type: mavMissionOK}})
+
+	// The vehicle now answers MISSION_REQUEST_INT for real, but never
+	// sends a fresh MISSION_ACK — so attempt 2 should time out waiting
+	// for one, NOT succeed off attempt 1's stale ack.
+	vehCtx, vehCancel := context.WithCancel(context.Background())
+	defer vehCancel()
+	vehConn := conn.New(vehLink, mavlink.NewCodec(nil), 1, 1)
+	go func() {
+		for {
+			f, from, err := vehConn.ReadFrame(vehCtx)
+			if err != nil {
+				return
+			}
+			if m, ok := f.Msg.(*mavlink.MissionCount); ok {
+				for seq := uint16(0); seq < m.Count; seq++ {
+					vehConn.Send(from, &mavlink.MissionRequestInt{Seq: seq, TargetSystem: f.SysID, TargetComponent: f.CompID})
+				}
+			}
+		}
+	}()
+
+	attempt2Ctx, cancel2 := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel2()
+	err = u.uploadOnce(attempt2Ctx, items)
+	if err == nil {
+		t.Fatal("attempt 2 succeeded off attempt 1's stale MISSION_ACK instead of waiting for its own")
+	}
+}
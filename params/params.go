@@ -0,0 +1,203 @@
+// Package params implements the MAVLink parameter protocol: pulling a
+// target's full parameter set with PARAM_REQUEST_LIST and gap-filling
+// with PARAM_REQUEST_READ, and setting individual values with PARAM_SET.
+package params
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/joseph-m-valdez/aetherforge/conn"
+	"github.com/joseph-m-valdez/aetherforge/mavlink"
+)
+
+// mavParamTypeReal32 is MAV_PARAM_TYPE_REAL32 (9): the only on-the-wire
+// param type this client produces, since Client's API is float64-typed
+// like most MAVLink GCS implementations regardless of the parameter's
+// actual storage type.
+const mavParamTypeReal32 = 9
+
+// stallTimeout is how long FetchAll waits between new PARAM_VALUEs
+// before assuming a gap and re-requesting missing indices.
+const stallTimeout = 2 * time.Second
+
+// Client streams and edits one target's parameter set. It does not read
+// from a link itself — call HandleFrame with every decoded frame your
+// read loop sees (mirroring router.Router.Handle) so the client can
+// observe PARAM_VALUE replies.
+type Client struct {
+	conn   *conn.Conn
+	peer   net.Addr
+	sysID  uint8
+	compID uint8
+
+	mu       sync.Mutex
+	byName   map[string]mavlink.ParamValue
+	byIndex  map[uint16]bool
+	expected int // -1 until the first PARAM_VALUE reports param_count
+	waiters  map[string]chan mavlink.ParamValue
+}
+
+// NewClient builds a Client targeting (sysID, compID) over c, sending to
+// peer.
+func NewClient(c *conn.Conn, peer net.Addr, sysID, compID uint8) *Client {
+	return &Client{
+		conn:     c,
+		peer:     peer,
+		sysID:    sysID,
+		compID:   compID,
+		byName:   make(map[string]mavlink.ParamValue),
+		byIndex:  make(map[uint16]bool),
+		expected: -1,
+		waiters:  make(map[string]chan mavlink.ParamValue),
+	}
+}
+
+// HandleFrame records f if it's a PARAM_VALUE for this client's target,
+// waking any Set call waiting on it. It returns whether f was a
+// PARAM_VALUE at all, so callers can fold it into their own dispatch.
+func (c *Client) HandleFrame(f *mavlink.Frame) bool {
+	pv, ok := f.Msg.(*mavlink.ParamValue)
+	if !ok {
+		return false
+	}
+	if f.SysID != c.sysID || f.CompID != c.compID {
+		return true
+	}
+
+	c.mu.Lock()
+	c.byName[pv.ParamID] = *pv
+	c.byIndex[pv.ParamIndex] = true
+	c.expected = int(pv.ParamCount)
+	if ch, ok := c.waiters[pv.ParamID]; ok {
+		select {
+		case ch <- *pv:
+		default:
+		}
+	}
+	c.mu.Unlock()
+	return true
+}
+
+// FetchAll issues PARAM_REQUEST_LIST and blocks until every parameter
+// has been streamed. If stallTimeout passes with no PARAM_VALUE at all —
+// the initial PARAM_REQUEST_LIST itself may have been lost — it's
+// re-sent; once at least one has arrived, further stalls re-request only
+// the missing indices rather than restarting the whole dump.
+func (c *Client) FetchAll(ctx context.Context) error {
+	req := &mavlink.ParamRequestList{TargetSystem: c.sysID, TargetComponent: c.compID}
+	if err := c.conn.Send(c.peer, req); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(stallTimeout)
+	defer timer.Stop()
+	lastSeen := -1
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			c.mu.Lock()
+			seen := len(c.byIndex)
+			expected := c.expected
+			c.mu.Unlock()
+
+			switch {
+			case expected >= 0 && seen >= expected:
+				return nil
+			case expected < 0:
+				if err := c.conn.Send(c.peer, req); err != nil {
+					return err
+				}
+			case seen == lastSeen:
+				if err := c.requestMissing(expected); err != nil {
+					return err
+				}
+			}
+			lastSeen = seen
+			timer.Reset(stallTimeout)
+		}
+	}
+}
+
+// requestMissing sends a targeted PARAM_REQUEST_READ for every index in
+// [0, expected) we haven't seen a PARAM_VALUE for yet.
+func (c *Client) requestMissing(expected int) error {
+	c.mu.Lock()
+	var missing []uint16
+	for i := 0; i < expected; i++ {
+		if !c.byIndex[uint16(i)] {
+			missing = append(missing, uint16(i))
+		}
+	}
+	c.mu.Unlock()
+
+	for _, idx := range missing {
+		msg := &mavlink.ParamRequestRead{ParamIndex: int16(idx), TargetSystem: c.sysID, TargetComponent: c.compID}
+		if err := c.conn.Send(c.peer, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get returns a previously-streamed parameter's value. Call FetchAll (or
+// wait for at least one matching PARAM_VALUE via HandleFrame) first.
+func (c *Client) Get(name string) (float64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.byName[name]
+	if !ok {
+		return 0, fmt.Errorf("params: %s not fetched yet", name)
+	}
+	return float64(v.ParamValue), nil
+}
+
+// Set sends PARAM_SET and waits for the target to echo it back as a
+// PARAM_VALUE, retrying with exponential backoff if it doesn't.
+func (c *Client) Set(ctx context.Context, name string, value float64) error {
+	ch := make(chan mavlink.ParamValue, 1)
+	c.mu.Lock()
+	c.waiters[name] = ch
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.waiters, name)
+		c.mu.Unlock()
+	}()
+
+	msg := &mavlink.ParamSet{
+		ParamValue:      float32(value),
+		TargetSystem:    c.sysID,
+		TargetComponent: c.compID,
+		ParamID:         name,
+		ParamType:       mavParamTypeReal32,
+	}
+
+	backoff := 200 * time.Millisecond
+	const maxAttempts = 5
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if err := c.conn.Send(c.peer, msg); err != nil {
+			return err
+		}
+		select {
+		case pv := <-ch:
+			if pv.ParamValue != float32(value) {
+				return fmt.Errorf("params: set %s: target echoed %v, want %v", name, pv.ParamValue, value)
+			}
+			c.mu.Lock()
+			c.byName[name] = pv
+			c.mu.Unlock()
+			return nil
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return fmt.Errorf("params: set %s: no confirmation after %d attempts", name, maxAttempts)
+}
@@ -0,0 +1,76 @@
+package params
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joseph-m-valdez/aetherforge/conn"
+	"github.com/joseph-m-valdez/aetherforge/link"
+	"github.com/joseph-m-valdez/aetherforge/mavlink"
+)
+
+// TestFetchAllRetriesLostRequestList simulates a vehicle that never sees
+// the first PARAM_REQUEST_LIST (e.g. dropped over UDP) and only answers
+// the second one, once FetchAll's stall timer re-sends it. Without that
+// retransmit, expected stays -1 forever and FetchAll just spins until
+// ctx is canceled.
+func TestFetchAllRetriesLostRequestList(t *testing.T) {
+	gcsLink, err := link.Open("udp://127.0.0.1:18201")
+	if err != nil {
+		t.Fatalf("link.Open gcs: %v", err)
+	}
+	defer gcsLink.Close()
+	vehLink, err := link.Open("udp://127.0.0.1:18202")
+	if err != nil {
+		t.Fatalf("link.Open veh: %v", err)
+	}
+	defer vehLink.Close()
+
+	gcsConn := conn.New(gcsLink, mavlink.NewCodec(nil), 255, 190)
+	vehConn := conn.New(vehLink, mavlink.NewCodec(nil), 1, 1)
+	vehAddr := &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 18202}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*stallTimeout+2*time.Second)
+	defer cancel()
+
+	var requestListSeen int32
+	go func() {
+		for {
+			f, from, err := vehConn.ReadFrame(ctx)
+			if err != nil {
+				return
+			}
+			if _, ok := f.Msg.(*mavlink.ParamRequestList); ok {
+				if atomic.AddInt32(&requestListSeen, 1) == 1 {
+					continue // drop the first PARAM_REQUEST_LIST entirely
+				}
+				vehConn.Send(from, &mavlink.ParamValue{ParamValue: 1.5, ParamCount: 1, ParamIndex: 0, ParamID: "THR_MAX"})
+			}
+		}
+	}()
+
+	c := NewClient(gcsConn, vehAddr, 1, 1)
+	go func() {
+		for {
+			f, _, err := gcsConn.ReadFrame(ctx)
+			if err != nil {
+				return
+			}
+			c.HandleFrame(f)
+		}
+	}()
+
+	if err := c.FetchAll(ctx); err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+	if got := atomic.LoadInt32(&requestListSeen); got < 2 {
+		t.Errorf("vehicle saw %d PARAM_REQUEST_LISTs, want at least 2 (FetchAll should have retried)", got)
+	}
+	v, err := c.Get("THR_MAX")
+	if err != nil || v != 1.5 {
+		t.Errorf("Get(THR_MAX) = %v, %v, want 1.5, nil", v, err)
+	}
+}
@@ -0,0 +1,39 @@
+package router
+
+// EventType identifies what changed about a Session.
+type EventType int
+
+const (
+	// OnConnect fires the first time a (sysid, compid) is seen.
+	OnConnect EventType = iota
+	// OnDisconnect fires when a session's heartbeat goes stale past
+	// Router.Timeout.
+	OnDisconnect
+	// OnModeChange fires when a HEARTBEAT's CustomMode differs from the
+	// session's previous value.
+	OnModeChange
+	// OnArmStateChange fires when the armed bit (base_mode & 0x80) flips.
+	OnArmStateChange
+)
+
+func (t EventType) String() string {
+	switch t {
+	case OnConnect:
+		return "connect"
+	case OnDisconnect:
+		return "disconnect"
+	case OnModeChange:
+		return "mode_change"
+	case OnArmStateChange:
+		return "arm_state_change"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is published to every Router.Subscribe channel whenever a session connects,
+// disconnects, or changes mode/arm state.
+type Event struct {
+	Type    EventType
+	Session Session
+}
@@ -0,0 +1,60 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Handler returns an http.Handler exposing the session table:
+//
+//	GET /vehicles        a JSON snapshot of every known session
+//	GET /vehicles/stream a text/event-stream of connect/disconnect/
+//	                     mode-change/arm-change events as they happen
+func (r *Router) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vehicles", r.handleVehicles)
+	mux.HandleFunc("/vehicles/stream", r.handleVehiclesStream)
+	return mux
+}
+
+func (r *Router) handleVehicles(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(r.Sessions()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (r *Router) handleVehiclesStream(w http.ResponseWriter, req *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	// Each stream gets its own subscriber channel, so this client's
+	// events aren't split with other SSE clients or structured logging
+	// (a slow client only loses events off its own channel — the send in
+	// publish is non-blocking — it never stalls the router or siblings).
+	events, cancel := r.Subscribe()
+	defer cancel()
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			body, err := json.Marshal(e)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, body)
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,185 @@
+// Package router keeps track of every vehicle (and gimbal, companion
+// computer, or ADS-B transponder) a GCS sees across all its links, keyed
+// by the (sysid, compid) pair MAVLink uses to distinguish them on a
+// shared channel.
+package router
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/joseph-m-valdez/aetherforge/link"
+	"github.com/joseph-m-valdez/aetherforge/mavlink"
+)
+
+// DefaultHeartbeatTimeout is how long a session can go without a
+// HEARTBEAT before Router considers it disconnected — the MAVLink
+// heartbeat-loss convention is 5 missed 1Hz heartbeats.
+const DefaultHeartbeatTimeout = 5 * time.Second
+
+// Session is everything the router knows about one (SysID, CompID).
+type Session struct {
+	SysID  uint8
+	CompID uint8
+
+	LastHeartbeat time.Time
+	RemoteAddr    net.Addr
+	Link          link.Link
+
+	Autopilot    uint8
+	Type         uint8
+	SystemStatus uint8
+	CustomMode   uint32
+	Armed        bool
+}
+
+func (s Session) key() sessionKey { return sessionKey{s.SysID, s.CompID} }
+
+type sessionKey struct {
+	sysID, compID uint8
+}
+
+// Router upserts a Session for every decoded frame and evicts sessions
+// that go quiet for longer than Timeout. It's safe for concurrent use.
+type Router struct {
+	Timeout time.Duration
+
+	mu       sync.RWMutex
+	sessions map[sessionKey]*Session
+
+	subMu sync.Mutex
+	subs  map[chan Event]struct{}
+}
+
+// NewRouter builds a Router. timeout <= 0 uses DefaultHeartbeatTimeout.
+func NewRouter(timeout time.Duration) *Router {
+	if timeout <= 0 {
+		timeout = DefaultHeartbeatTimeout
+	}
+	return &Router{
+		Timeout:  timeout,
+		sessions: make(map[sessionKey]*Session),
+		subs:     make(map[chan Event]struct{}),
+	}
+}
+
+// Subscribe registers a new, independent subscriber for OnConnect/
+// OnDisconnect/OnModeChange/OnArmStateChange events, returning its
+// channel and a cancel func to unregister it. Every subscriber gets its
+// own buffered channel — plain Go channels only deliver each value to
+// one receiver, so sharing a single channel across e.g. structured
+// logging and several SSE clients would have them stealing events from
+// each other. Callers must call cancel when done to free the channel.
+func (r *Router) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, 256)
+	r.subMu.Lock()
+	r.subs[ch] = struct{}{}
+	r.subMu.Unlock()
+
+	cancel := func() {
+		r.subMu.Lock()
+		delete(r.subs, ch)
+		r.subMu.Unlock()
+	}
+	return ch, cancel
+}
+
+// Handle upserts the session for frame's (sysid, compid), recording which
+// link and remote address it arrived on, and publishes any state-change
+// events the update implies. Non-HEARTBEAT frames still refresh
+// LastHeartbeat/RemoteAddr/Link (anything on the wire proves the sender
+// is still there) but only a HEARTBEAT carries mode/arm/type fields.
+func (r *Router) Handle(frame *mavlink.Frame, from net.Addr, l link.Link) {
+	key := sessionKey{frame.SysID, frame.CompID}
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sess, existed := r.sessions[key]
+	if !existed {
+		sess = &Session{SysID: frame.SysID, CompID: frame.CompID}
+		r.sessions[key] = sess
+	}
+	sess.LastHeartbeat = now
+	sess.RemoteAddr = from
+	sess.Link = l
+
+	prevMode, prevArmed := sess.CustomMode, sess.Armed
+	if hb, ok := frame.Msg.(*mavlink.Heartbeat); ok {
+		sess.Autopilot = hb.Autopilot
+		sess.Type = hb.Type
+		sess.SystemStatus = hb.SystemStatus
+		sess.CustomMode = hb.CustomMode
+		sess.Armed = hb.Armed()
+	}
+
+	// Publish connect last so subscribers see the session's fields (type,
+	// autopilot, ...) already populated if this first sighting happened
+	// to be a HEARTBEAT, which it almost always is.
+	if !existed {
+		r.publish(Event{Type: OnConnect, Session: *sess})
+		return
+	}
+	if sess.CustomMode != prevMode {
+		r.publish(Event{Type: OnModeChange, Session: *sess})
+	}
+	if sess.Armed != prevArmed {
+		r.publish(Event{Type: OnArmStateChange, Session: *sess})
+	}
+}
+
+// Sweep evicts any session whose last heartbeat is older than Timeout,
+// publishing an OnDisconnect for each. Callers typically run it
+// periodically from a ticker (see Run).
+func (r *Router) Sweep(now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key, sess := range r.sessions {
+		if now.Sub(sess.LastHeartbeat) > r.Timeout {
+			delete(r.sessions, key)
+			r.publish(Event{Type: OnDisconnect, Session: *sess})
+		}
+	}
+}
+
+// Run sweeps for expired sessions every Timeout/2 until done is closed.
+func (r *Router) Run(done <-chan struct{}) {
+	ticker := time.NewTicker(r.Timeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			r.Sweep(now)
+		}
+	}
+}
+
+// Sessions returns a point-in-time snapshot of every known session.
+func (r *Router) Sessions() []Session {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]Session, 0, len(r.sessions))
+	for _, sess := range r.sessions {
+		out = append(out, *sess)
+	}
+	return out
+}
+
+// publish fans e out to every current subscriber. Each send is
+// non-blocking: subscriber channels are sized generously, and a dropped
+// event (slow consumer) shouldn't stall packet handling or other
+// subscribers.
+func (r *Router) publish(e Event) {
+	r.subMu.Lock()
+	defer r.subMu.Unlock()
+	for ch := range r.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
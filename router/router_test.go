@@ -0,0 +1,130 @@
+package router
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joseph-m-valdez/aetherforge/mavlink"
+)
+
+func heartbeatFrame(sysID, compID uint8, mode uint32, armed bool) *mavlink.Frame {
+	var baseMode uint8
+	if armed {
+		baseMode = 0x80
+	}
+	return &mavlink.Frame{
+		SysID:  sysID,
+		CompID: compID,
+		Msg:    &mavlink.Heartbeat{BaseMode: baseMode, CustomMode: mode},
+	}
+}
+
+// TestHandlePublishesConnectModeAndArmEvents drives one session through
+// first-sighting, a mode change, and an arm-state change, checking each
+// produces exactly the event Handle documents.
+func TestHandlePublishesConnectModeAndArmEvents(t *testing.T) {
+	r := NewRouter(time.Second)
+	ch, cancel := r.Subscribe()
+	defer cancel()
+
+	r.Handle(heartbeatFrame(1, 1, 0, false), nil, nil)
+	select {
+	case e := <-ch:
+		if e.Type != OnConnect {
+			t.Fatalf("first Handle published %v, want OnConnect", e.Type)
+		}
+	default:
+		t.Fatal("first Handle published nothing, want OnConnect")
+	}
+
+	r.Handle(heartbeatFrame(1, 1, 1, false), nil, nil)
+	select {
+	case e := <-ch:
+		if e.Type != OnModeChange {
+			t.Fatalf("mode-changing Handle published %v, want OnModeChange", e.Type)
+		}
+	default:
+		t.Fatal("mode-changing Handle published nothing, want OnModeChange")
+	}
+
+	r.Handle(heartbeatFrame(1, 1, 1, true), nil, nil)
+	select {
+	case e := <-ch:
+		if e.Type != OnArmStateChange {
+			t.Fatalf("arming Handle published %v, want OnArmStateChange", e.Type)
+		}
+	default:
+		t.Fatal("arming Handle published nothing, want OnArmStateChange")
+	}
+
+	// A repeat heartbeat with nothing changed shouldn't publish anything.
+	r.Handle(heartbeatFrame(1, 1, 1, true), nil, nil)
+	select {
+	case e := <-ch:
+		t.Fatalf("unchanged Handle published %v, want nothing", e.Type)
+	default:
+	}
+}
+
+// TestSweepEvictsStaleSessions checks Sweep evicts every session whose
+// heartbeat is older than Timeout and publishes OnDisconnect for each.
+func TestSweepEvictsStaleSessions(t *testing.T) {
+	r := NewRouter(time.Second)
+	ch, cancel := r.Subscribe()
+	defer cancel()
+
+	r.Handle(heartbeatFrame(1, 1, 0, false), nil, nil)
+	r.Handle(heartbeatFrame(2, 1, 0, false), nil, nil)
+	<-ch // OnConnect for sysid 1
+	<-ch // OnConnect for sysid 2
+
+	r.Sweep(time.Now().Add(2 * time.Second))
+
+	seen := map[uint8]bool{}
+	for i := 0; i < 2; i++ {
+		select {
+		case e := <-ch:
+			if e.Type != OnDisconnect {
+				t.Fatalf("Sweep published %v, want OnDisconnect", e.Type)
+			}
+			seen[e.Session.SysID] = true
+		default:
+			t.Fatal("Sweep published fewer OnDisconnect events than expected")
+		}
+	}
+	if !seen[1] || !seen[2] {
+		t.Fatalf("Sweep disconnected sysids %v, want both 1 and 2", seen)
+	}
+	if got := r.Sessions(); len(got) != 0 {
+		t.Errorf("Sessions() after Sweep = %v, want empty", got)
+	}
+}
+
+// TestSubscribeFansOutIndependently is the regression test for the
+// fan-out fix: two independent subscribers must each see every event, not
+// steal events from one another the way a single shared channel would.
+func TestSubscribeFansOutIndependently(t *testing.T) {
+	r := NewRouter(time.Second)
+	chA, cancelA := r.Subscribe()
+	defer cancelA()
+	chB, cancelB := r.Subscribe()
+	defer cancelB()
+
+	r.Handle(heartbeatFrame(1, 1, 0, false), nil, nil)
+	r.Handle(heartbeatFrame(1, 1, 1, false), nil, nil)
+
+	for _, ch := range []<-chan Event{chA, chB} {
+		var got []EventType
+		for i := 0; i < 2; i++ {
+			select {
+			case e := <-ch:
+				got = append(got, e.Type)
+			default:
+				t.Fatalf("subscriber missed an event, got %v so far", got)
+			}
+		}
+		if got[0] != OnConnect || got[1] != OnModeChange {
+			t.Errorf("subscriber events = %v, want [OnConnect OnModeChange]", got)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package router
+
+import (
+	"fmt"
+
+	"github.com/joseph-m-valdez/aetherforge/link"
+	"github.com/joseph-m-valdez/aetherforge/mavlink"
+)
+
+// Send encodes msg and routes it to the session for (sysID, compID),
+// using that session's recorded Link and RemoteAddr. A sysID of 0
+// broadcasts msg to every known session instead (the MAVLink convention
+// for "all systems").
+func (r *Router) Send(codec *mavlink.Codec, ourSysID, ourCompID, seq uint8, sysID, compID uint8, msg mavlink.Message) error {
+	if sysID == 0 {
+		return r.broadcast(codec, ourSysID, ourCompID, seq, msg)
+	}
+
+	r.mu.RLock()
+	sess, ok := r.sessions[sessionKey{sysID, compID}]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("router: no known session for sysid=%d compid=%d", sysID, compID)
+	}
+
+	frame, err := codec.Encode(ourSysID, ourCompID, seq, msg, nil)
+	if err != nil {
+		return err
+	}
+	return sess.Link.WriteFrame(link.Frame(frame), sess.RemoteAddr)
+}
+
+func (r *Router) broadcast(codec *mavlink.Codec, ourSysID, ourCompID, seq uint8, msg mavlink.Message) error {
+	frame, err := codec.Encode(ourSysID, ourCompID, seq, msg, nil)
+	if err != nil {
+		return err
+	}
+	for _, sess := range r.Sessions() {
+		if err := sess.Link.WriteFrame(link.Frame(frame), sess.RemoteAddr); err != nil {
+			return fmt.Errorf("router: broadcast to sysid=%d compid=%d: %w", sess.SysID, sess.CompID, err)
+		}
+	}
+	return nil
+}